@@ -3,6 +3,7 @@ module github.com/dastergon/kubectl-janitor
 go 1.15
 
 require (
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.4.0
@@ -11,4 +12,5 @@ require (
 	k8s.io/cli-runtime v0.19.4
 	k8s.io/client-go v0.19.4
 	k8s.io/kubectl v0.19.4
+	sigs.k8s.io/yaml v1.2.0
 )
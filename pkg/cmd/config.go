@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/yaml"
+)
+
+// janitorConfig holds the settings teams can share in the janitor config file.
+type janitorConfig struct {
+	// Hints are matched before the built-in remediation hints.
+	Hints []hint `json:"hints,omitempty"`
+}
+
+// getDefaultJanitorConfigPath returns the path of the janitor config file used
+// when --janitor-config is not given.
+func getDefaultJanitorConfigPath() string {
+	return filepath.Join(homedir.HomeDir(), ".kube", "janitor.yaml")
+}
+
+// loadJanitorConfig reads the janitor config file. A missing file is only an
+// error when its path was given explicitly.
+func loadJanitorConfig(path string) (janitorConfig, error) {
+	var config janitorConfig
+
+	explicit := path != ""
+	if !explicit {
+		path = getDefaultJanitorConfigPath()
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, fmt.Errorf("invalid janitor config %s: %v", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadJanitorConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	err := ioutil.WriteFile(valid, []byte(`hints:
+- reason: CrashLoopBackOff
+  exitCode: 3
+  explanation: The database migration failed.
+  commands:
+  - kubectl logs {name} -n {namespace} -c {container} --previous
+`), 0600)
+	assert.NoError(t, err)
+
+	unknown := filepath.Join(dir, "unknown.yaml")
+	err = ioutil.WriteFile(unknown, []byte("hint: []\n"), 0600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    janitorConfig
+		wantErr bool
+	}{
+		{
+			name: "hints are expected to be read from the config",
+			path: valid,
+			want: janitorConfig{Hints: []hint{{
+				Reason:      "CrashLoopBackOff",
+				ExitCode:    int32Ptr(3),
+				Explanation: "The database migration failed.",
+				Commands:    []string{"kubectl logs {name} -n {namespace} -c {container} --previous"},
+			}}},
+		},
+		{
+			name:    "unknown fields are expected to be rejected",
+			path:    unknown,
+			wantErr: true,
+		},
+		{
+			name:    "missing config given explicitly is expected to be an error",
+			path:    filepath.Join(dir, "missing.yaml"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := loadJanitorConfig(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, config)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// missedScheduleGrace is the tolerance given to the CronJob controller
+// before a scheduled run is considered missed.
+const missedScheduleGrace = time.Minute
+
+// UnhealthyCronJobsOptions embeds JanitorOptions struct.
+type UnhealthyCronJobsOptions struct {
+	JanitorOptions
+	failedJobs int
+}
+
+// newUnhealthyCronJobsOptions creates an instance of UnhealthyCronJobsOptions.
+func newUnhealthyCronJobsOptions(options JanitorOptions) *UnhealthyCronJobsOptions {
+	return &UnhealthyCronJobsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newUnhealthyCronJobsCommand returns a cobra command wrapping UnhealthyCronJobsOptions.
+func newUnhealthyCronJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newUnhealthyCronJobsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "unhealthy",
+		Short:        "List CronJobs that are suspended, missed their schedule or keep failing",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().IntVar(&o.failedJobs, "failed-jobs", 3, "Number of most recent child Jobs that must all have failed to flag a CronJob (0 disables the check).")
+
+	return cmd
+}
+
+// Run lists CronJobs in an unhealthy state.
+func (o *UnhealthyCronJobsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	cronJobs, err := client.BatchV1beta1().CronJobs(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	jobs, err := client.BatchV1().Jobs(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	children := make(map[string][]batchv1.Job)
+	for _, job := range jobs.Items {
+		if owner := metav1.GetControllerOf(&job); owner != nil && owner.Kind == "CronJob" {
+			children[string(owner.UID)] = append(children[string(owner.UID)], job)
+		}
+	}
+
+	now := time.Now()
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, cronJob := range cronJobs.Items {
+		problems := getCronJobProblems(cronJob, children[string(cronJob.UID)], o.failedJobs, now)
+		if len(problems) == 0 {
+			continue
+		}
+
+		lastSchedule := "<none>"
+		if cronJob.Status.LastScheduleTime != nil {
+			lastSchedule = getAge(*cronJob.Status.LastScheduleTime)
+		}
+
+		age := getAge(cronJob.CreationTimestamp)
+		row := []string{cronJob.Name, cronJob.Spec.Schedule, strings.Join(problems, ","), lastSchedule, getCronJobNextRun(cronJob, now), age}
+		if o.allNamespaces {
+			row = append([]string{cronJob.Namespace}, row...)
+		}
+		uids = append(uids, cronJob.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "SCHEDULE", "PROBLEMS", "LAST SCHEDULE", "NEXT RUN", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getCronJobProblems returns the reasons a CronJob is considered unhealthy.
+// The jobs are the child Jobs owned by the CronJob.
+func getCronJobProblems(cronJob batchv1beta1.CronJob, jobs []batchv1.Job, failedJobs int, now time.Time) []string {
+	var problems []string
+
+	suspended := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+	if suspended {
+		problems = append(problems, "Suspended")
+	}
+
+	schedule, err := parseCronJobSchedule(cronJob.Spec.Schedule)
+	if err != nil {
+		problems = append(problems, "InvalidSchedule")
+	} else if !suspended {
+		last := cronJob.CreationTimestamp.Time
+		if cronJob.Status.LastScheduleTime != nil {
+			last = cronJob.Status.LastScheduleTime.Time
+		}
+
+		deadline := missedScheduleGrace
+		if cronJob.Spec.StartingDeadlineSeconds != nil {
+			if d := time.Duration(*cronJob.Spec.StartingDeadlineSeconds) * time.Second; d > deadline {
+				deadline = d
+			}
+		}
+
+		if schedule.Next(last).Add(deadline).Before(now) {
+			problems = append(problems, "MissedSchedule")
+		}
+	}
+
+	if failedJobs > 0 {
+		var finished []batchv1.Job
+		for _, job := range jobs {
+			if isJobFailed(job) || job.Status.CompletionTime != nil {
+				finished = append(finished, job)
+			}
+		}
+		sort.Slice(finished, func(i, j int) bool {
+			return finished[j].CreationTimestamp.Before(&finished[i].CreationTimestamp)
+		})
+
+		if len(finished) >= failedJobs {
+			allFailed := true
+			for _, job := range finished[:failedJobs] {
+				if !isJobFailed(job) {
+					allFailed = false
+					break
+				}
+			}
+			if allFailed {
+				problems = append(problems, "RepeatedFailures")
+			}
+		}
+	}
+
+	switch cronJob.Spec.ConcurrencyPolicy {
+	case batchv1beta1.ForbidConcurrent, batchv1beta1.ReplaceConcurrent:
+		if len(cronJob.Status.Active) > 1 {
+			problems = append(problems, "ConcurrencyExceeded")
+		}
+	}
+
+	return problems
+}
+
+// getCronJobNextRun returns the next time the CronJob is expected to run.
+func getCronJobNextRun(cronJob batchv1beta1.CronJob, now time.Time) string {
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		return "<none>"
+	}
+
+	schedule, err := parseCronJobSchedule(cronJob.Spec.Schedule)
+	if err != nil {
+		return "<none>"
+	}
+	return schedule.Next(now).UTC().Format(time.RFC3339)
+}
+
+// parseCronJobSchedule parses the schedule of a CronJob in UTC, the timezone
+// the CronJob controller normally evaluates schedules in, rather than in the
+// local timezone of the machine running kubectl.
+func parseCronJobSchedule(schedule string) (cron.Schedule, error) {
+	if !strings.HasPrefix(schedule, "CRON_TZ=") && !strings.HasPrefix(schedule, "TZ=") {
+		schedule = "CRON_TZ=UTC " + schedule
+	}
+	return cron.ParseStandard(schedule)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetCronJobProblems(t *testing.T) {
+	now := time.Date(2020, time.December, 1, 12, 30, 0, 0, time.UTC)
+	suspend := true
+
+	tests := []struct {
+		name     string
+		cronJob  batchv1beta1.CronJob
+		jobs     []batchv1.Job
+		expected []string
+	}{
+		{
+			name: "CronJob that ran on schedule is expected to be healthy",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{Schedule: "0 * * * *"},
+				Status: batchv1beta1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "Suspended CronJob is not expected to miss its schedule",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{Schedule: "0 * * * *", Suspend: &suspend},
+				Status: batchv1beta1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: now.Add(-48 * time.Hour)},
+				},
+			},
+			expected: []string{"Suspended"},
+		},
+		{
+			name: "CronJob that has not run for hours is expected to miss its schedule",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{Schedule: "0 * * * *"},
+				Status: batchv1beta1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: now.Add(-3 * time.Hour)},
+				},
+			},
+			expected: []string{"MissedSchedule"},
+		},
+		{
+			name: "CronJob with an invalid schedule is expected to be reported",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{Schedule: "every hour"},
+			},
+			expected: []string{"InvalidSchedule"},
+		},
+		{
+			name: "CronJob whose most recent Jobs failed is expected to be reported",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{Schedule: "0 * * * *"},
+				Status: batchv1beta1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+				},
+			},
+			jobs: []batchv1.Job{
+				newTestCompletedJob(testNamespace, "backup", now.Add(-4*time.Hour)),
+				newTestFailedJob(testNamespace, "backup", now.Add(-3*time.Hour)),
+				newTestFailedJob(testNamespace, "backup", now.Add(-2*time.Hour)),
+				newTestFailedJob(testNamespace, "backup", now.Add(-1*time.Hour)),
+			},
+			expected: []string{"RepeatedFailures"},
+		},
+		{
+			name: "CronJob that recovered after failures is expected to be healthy",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{Schedule: "0 * * * *"},
+				Status: batchv1beta1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+				},
+			},
+			jobs: []batchv1.Job{
+				newTestFailedJob(testNamespace, "backup", now.Add(-3*time.Hour)),
+				newTestFailedJob(testNamespace, "backup", now.Add(-2*time.Hour)),
+				newTestCompletedJob(testNamespace, "backup", now.Add(-1*time.Hour)),
+			},
+			expected: nil,
+		},
+		{
+			name: "CronJob with more active Jobs than its concurrency policy allows is expected to be reported",
+			cronJob: batchv1beta1.CronJob{
+				Spec: batchv1beta1.CronJobSpec{
+					Schedule:          "0 * * * *",
+					ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+				},
+				Status: batchv1beta1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+					Active:           []corev1.ObjectReference{{Name: "a"}, {Name: "b"}},
+				},
+			},
+			expected: []string{"ConcurrencyExceeded"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getCronJobProblems(tc.cronJob, tc.jobs, 3, now)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+// TestGetCronJobScheduleOutsideUTC runs kubectl in a timezone other than the
+// one of the CronJob controller. It changes time.Local, so it must not run in
+// parallel with other tests.
+func TestGetCronJobScheduleOutsideUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	now := time.Date(2020, time.December, 1, 12, 30, 0, 0, time.UTC)
+	cronJob := batchv1beta1.CronJob{
+		Spec: batchv1beta1.CronJobSpec{Schedule: "0 3 * * *"},
+		Status: batchv1beta1.CronJobStatus{
+			LastScheduleTime: &metav1.Time{Time: time.Date(2020, time.December, 1, 3, 0, 0, 0, time.UTC).In(time.Local)},
+		},
+	}
+
+	assert.Empty(t, getCronJobProblems(cronJob, nil, 3, now.In(time.Local)))
+	assert.Equal(t, "2020-12-02T03:00:00Z", getCronJobNextRun(cronJob, now.In(time.Local)))
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newCronJobsCommand provides the base command when called without any subcommands.
+func newCronJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "cronjobs",
+		Short:        "Find CronJobs in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newUnhealthyCronJobsCommand(factory, options))
+
+	return cmd
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// maxDigestObjects is the number of top involved objects shown for each reason.
+const maxDigestObjects = 3
+
+// WarningsEventsOptions embeds JanitorOptions struct.
+type WarningsEventsOptions struct {
+	JanitorOptions
+	since time.Duration
+}
+
+// warningEvent is a Warning Event read from either the events.k8s.io/v1 or the core/v1 API.
+type warningEvent struct {
+	namespace string
+	reason    string
+	kind      string
+	name      string
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// eventDigest aggregates the Warning Events sharing the same reason and kind of involved object.
+type eventDigest struct {
+	reason    string
+	kind      string
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+	objects   []string
+}
+
+// newWarningsEventsOptions creates an instance of WarningsEventsOptions.
+func newWarningsEventsOptions(options JanitorOptions) *WarningsEventsOptions {
+	return &WarningsEventsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newWarningsEventsCommand returns a cobra command wrapping WarningsEventsOptions.
+func newWarningsEventsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newWarningsEventsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "warnings",
+		Short:        "Aggregate the Warning Events by reason and kind of involved object",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.since, "since", time.Hour, "Only aggregate the Events last seen within this duration. Their total count and first seen time cover all their occurrences, including older ones.")
+
+	return cmd
+}
+
+// Run prints a digest of the Warning Events.
+func (o *WarningsEventsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	events, err := listWarningEvents(ctx, client, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	var matrix [][]string
+
+	for _, digest := range digestWarningEvents(events, now.Add(-o.since), o.allNamespaces) {
+		firstSeen := duration.HumanDuration(now.Sub(digest.firstSeen))
+		lastSeen := duration.HumanDuration(now.Sub(digest.lastSeen))
+		row := []string{digest.reason, digest.kind, strconv.Itoa(digest.count), firstSeen, lastSeen, strings.Join(digest.objects, ",")}
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"REASON", "KIND", "TOTAL COUNT", "FIRST SEEN", "LAST SEEN", "TOP OBJECTS"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printClusterResults(headers, matrix, nil, "TOTAL COUNT")
+}
+
+// listWarningEvents returns the Warning Events of the namespace from the
+// events.k8s.io/v1 API, or from the core/v1 API when the former is not served.
+func listWarningEvents(ctx context.Context, client kubernetes.Interface, namespace string) ([]warningEvent, error) {
+	options := metav1.ListOptions{FieldSelector: "type=" + corev1.EventTypeWarning}
+
+	var events []warningEvent
+
+	list, err := client.EventsV1().Events(namespace).List(ctx, options)
+	if err == nil {
+		for _, event := range list.Items {
+			events = append(events, newWarningEventFromV1(event))
+		}
+		return events, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	coreList, err := client.CoreV1().Events(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range coreList.Items {
+		events = append(events, newWarningEventFromCore(event))
+	}
+	return events, nil
+}
+
+// newWarningEventFromV1 converts an events.k8s.io/v1 Event, which records repeated
+// occurrences either in its series or in its deprecated fields.
+func newWarningEventFromV1(event eventsv1.Event) warningEvent {
+	e := warningEvent{
+		namespace: event.Regarding.Namespace,
+		reason:    event.Reason,
+		kind:      event.Regarding.Kind,
+		name:      event.Regarding.Name,
+		count:     1,
+		firstSeen: event.EventTime.Time,
+		lastSeen:  event.EventTime.Time,
+	}
+
+	if !event.DeprecatedFirstTimestamp.IsZero() {
+		e.firstSeen = event.DeprecatedFirstTimestamp.Time
+	}
+	if !event.DeprecatedLastTimestamp.IsZero() {
+		e.lastSeen = event.DeprecatedLastTimestamp.Time
+	}
+	if event.DeprecatedCount > 0 {
+		e.count = int(event.DeprecatedCount)
+	}
+
+	if event.Series != nil {
+		e.count = int(event.Series.Count)
+		e.lastSeen = event.Series.LastObservedTime.Time
+	}
+
+	if e.firstSeen.IsZero() {
+		e.firstSeen = event.CreationTimestamp.Time
+	}
+	if e.lastSeen.IsZero() {
+		e.lastSeen = e.firstSeen
+	}
+
+	return e
+}
+
+// newWarningEventFromCore converts a core/v1 Event.
+func newWarningEventFromCore(event corev1.Event) warningEvent {
+	e := warningEvent{
+		namespace: event.InvolvedObject.Namespace,
+		reason:    event.Reason,
+		kind:      event.InvolvedObject.Kind,
+		name:      event.InvolvedObject.Name,
+		count:     1,
+		firstSeen: event.FirstTimestamp.Time,
+		lastSeen:  getEventTime(event),
+	}
+
+	if event.Count > 0 {
+		e.count = int(event.Count)
+	}
+	if event.Series != nil {
+		e.count = int(event.Series.Count)
+		e.lastSeen = event.Series.LastObservedTime.Time
+	}
+
+	if e.firstSeen.IsZero() {
+		e.firstSeen = e.lastSeen
+	}
+
+	return e
+}
+
+// digestWarningEvents aggregates the Events last seen after since by reason and
+// kind of involved object, sorted by decreasing number of occurrences. An Event
+// only records its total count and first occurrence, so the digests count every
+// occurrence of those Events, including the ones before since.
+func digestWarningEvents(events []warningEvent, since time.Time, allNamespaces bool) []eventDigest {
+	type digestKey struct{ reason, kind string }
+
+	digests := make(map[digestKey]*eventDigest)
+	objectCounts := make(map[digestKey]map[string]int)
+
+	for _, event := range events {
+		if event.lastSeen.Before(since) {
+			continue
+		}
+
+		key := digestKey{event.reason, event.kind}
+		digest, ok := digests[key]
+		if !ok {
+			digest = &eventDigest{reason: event.reason, kind: event.kind, firstSeen: event.firstSeen, lastSeen: event.lastSeen}
+			digests[key] = digest
+			objectCounts[key] = make(map[string]int)
+		}
+
+		digest.count += event.count
+		if event.firstSeen.Before(digest.firstSeen) {
+			digest.firstSeen = event.firstSeen
+		}
+		if event.lastSeen.After(digest.lastSeen) {
+			digest.lastSeen = event.lastSeen
+		}
+
+		name := event.name
+		if allNamespaces && event.namespace != "" {
+			name = event.namespace + "/" + name
+		}
+		objectCounts[key][name] += event.count
+	}
+
+	var result []eventDigest
+	for key, digest := range digests {
+		var names []string
+		for name := range objectCounts[key] {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			ci, cj := objectCounts[key][names[i]], objectCounts[key][names[j]]
+			if ci != cj {
+				return ci > cj
+			}
+			return names[i] < names[j]
+		})
+
+		for i, name := range names {
+			if i == maxDigestObjects {
+				break
+			}
+			digest.objects = append(digest.objects, fmt.Sprintf("%s(%d)", name, objectCounts[key][name]))
+		}
+		result = append(result, *digest)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].count != result[j].count {
+			return result[i].count > result[j].count
+		}
+		if result[i].reason != result[j].reason {
+			return result[i].reason < result[j].reason
+		}
+		return result[i].kind < result[j].kind
+	})
+
+	return result
+}
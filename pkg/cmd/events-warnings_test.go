@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewWarningEvent(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	first := now.Add(-time.Hour)
+	regarding := corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web-1"}
+
+	withSeries := newWarningEventFromV1(eventsv1.Event{
+		Reason:                   "BackOff",
+		Regarding:                regarding,
+		EventTime:                metav1.MicroTime{Time: first},
+		DeprecatedFirstTimestamp: metav1.Time{Time: first},
+		Series:                   &eventsv1.EventSeries{Count: 7, LastObservedTime: metav1.MicroTime{Time: now}},
+	})
+	assert.Equal(t, warningEvent{namespace: "default", reason: "BackOff", kind: "Pod", name: "web-1", count: 7, firstSeen: first, lastSeen: now}, withSeries)
+
+	deprecated := newWarningEventFromV1(eventsv1.Event{
+		Reason:                   "FailedMount",
+		Regarding:                regarding,
+		DeprecatedFirstTimestamp: metav1.Time{Time: first},
+		DeprecatedLastTimestamp:  metav1.Time{Time: now},
+		DeprecatedCount:          3,
+	})
+	assert.Equal(t, warningEvent{namespace: "default", reason: "FailedMount", kind: "Pod", name: "web-1", count: 3, firstSeen: first, lastSeen: now}, deprecated)
+
+	core := newWarningEventFromCore(corev1.Event{
+		Reason:         "Unhealthy",
+		InvolvedObject: regarding,
+		FirstTimestamp: metav1.Time{Time: first},
+		LastTimestamp:  metav1.Time{Time: now},
+	})
+	assert.Equal(t, warningEvent{namespace: "default", reason: "Unhealthy", kind: "Pod", name: "web-1", count: 1, firstSeen: first, lastSeen: now}, core)
+}
+
+func TestDigestWarningEvents(t *testing.T) {
+	now := time.Now()
+	events := []warningEvent{
+		newTestWarningEvent("BackOff", "Pod", "default", "web-1", 10, now.Add(-time.Minute)),
+		newTestWarningEvent("BackOff", "Pod", "default", "web-2", 2, now.Add(-2*time.Minute)),
+		newTestWarningEvent("BackOff", "Pod", "batch", "job-1", 4, now.Add(-3*time.Minute)),
+		newTestWarningEvent("BackOff", "Pod", "default", "api-1", 1, now.Add(-4*time.Minute)),
+		newTestWarningEvent("FailedCreate", "ReplicaSet", "default", "web", 5, now.Add(-5*time.Minute)),
+		newTestWarningEvent("FailedMount", "Pod", "default", "db-0", 20, now.Add(-2*time.Hour)),
+	}
+
+	// An Event still recurring within the window is counted over its whole lifetime.
+	recurring := newTestWarningEvent("Unhealthy", "Pod", "default", "db-0", 50, now.Add(-10*time.Minute))
+	recurring.firstSeen = now.Add(-3 * time.Hour)
+	events = append(events, recurring)
+
+	tests := []struct {
+		name          string
+		allNamespaces bool
+		want          []eventDigest
+	}{
+		{
+			name: "expect Events within the window aggregated by reason and kind, with the top objects",
+			want: []eventDigest{
+				{reason: "Unhealthy", kind: "Pod", count: 50, firstSeen: now.Add(-3 * time.Hour), lastSeen: now.Add(-10 * time.Minute), objects: []string{"db-0(50)"}},
+				{reason: "BackOff", kind: "Pod", count: 17, firstSeen: now.Add(-5 * time.Minute), lastSeen: now.Add(-time.Minute), objects: []string{"web-1(10)", "job-1(4)", "web-2(2)"}},
+				{reason: "FailedCreate", kind: "ReplicaSet", count: 5, firstSeen: now.Add(-6 * time.Minute), lastSeen: now.Add(-5 * time.Minute), objects: []string{"web(5)"}},
+			},
+		},
+		{
+			name:          "expect the Namespace of the top objects across all Namespaces",
+			allNamespaces: true,
+			want: []eventDigest{
+				{reason: "Unhealthy", kind: "Pod", count: 50, firstSeen: now.Add(-3 * time.Hour), lastSeen: now.Add(-10 * time.Minute), objects: []string{"default/db-0(50)"}},
+				{reason: "BackOff", kind: "Pod", count: 17, firstSeen: now.Add(-5 * time.Minute), lastSeen: now.Add(-time.Minute), objects: []string{"default/web-1(10)", "batch/job-1(4)", "default/web-2(2)"}},
+				{reason: "FailedCreate", kind: "ReplicaSet", count: 5, firstSeen: now.Add(-6 * time.Minute), lastSeen: now.Add(-5 * time.Minute), objects: []string{"default/web(5)"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, digestWarningEvents(events, now.Add(-time.Hour), tt.allNamespaces))
+		})
+	}
+}
+
+func TestWarningsEventsRunJSON(t *testing.T) {
+	now := time.Now()
+	event := eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "db-0.1"},
+		Reason:     "Unhealthy",
+		Type:       corev1.EventTypeWarning,
+		Regarding:  corev1.ObjectReference{Kind: "Pod", Namespace: testNamespace, Name: "db-0"},
+		EventTime:  metav1.MicroTime{Time: now.Add(-3 * time.Hour)},
+		Series:     &eventsv1.EventSeries{Count: 50, LastObservedTime: metav1.MicroTime{Time: now.Add(-10 * time.Minute)}},
+	}
+
+	options, _, out, _ := newFakeJanitorOptions(fake.NewSimpleClientset(&event))
+	options.output = outputJSON
+	o := newWarningsEventsOptions(options)
+	o.since = time.Hour
+
+	err := o.Run(context.Background(), false)
+	assert.NoError(t, err)
+
+	var got []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, float64(50), got[0]["totalCount"])
+}
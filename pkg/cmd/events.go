@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newEventsCommand provides the base command when called without any subcommands.
+func newEventsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "events",
+		Short:        "Summarize the Events reporting problems",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newWarningsEventsCommand(factory, options))
+
+	return cmd
+}
@@ -0,0 +1,751 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// maxOwnerDepth bounds the walk up the ownerReferences of the explained object.
+const maxOwnerDepth = 10
+
+// explainKinds maps the accepted spellings of the supported kinds to their Kind.
+var explainKinds = map[string]string{
+	"pod":                    "Pod",
+	"pods":                   "Pod",
+	"po":                     "Pod",
+	"job":                    "Job",
+	"jobs":                   "Job",
+	"jobs.batch":             "Job",
+	"persistentvolumeclaim":  "PersistentVolumeClaim",
+	"persistentvolumeclaims": "PersistentVolumeClaim",
+	"pvc":                    "PersistentVolumeClaim",
+}
+
+// ExplainOptions embeds JanitorOptions struct.
+type ExplainOptions struct {
+	JanitorOptions
+}
+
+// explanation holds the diagnosis of a single object.
+type explanation struct {
+	obj        metav1.Object
+	conditions []explainCondition
+	// containers holds the rows of the containers of a Pod, or of the Pods of a Job
+	// or the Pods mounting a PersistentVolumeClaim, under their headers.
+	containerHeaders []string
+	containers       [][]string
+	references       [][]string
+	causes           []rootCause
+}
+
+// explainCondition is a status condition of the explained object.
+type explainCondition struct {
+	conditionType      string
+	status             corev1.ConditionStatus
+	reason             string
+	message            string
+	lastTransitionTime metav1.Time
+}
+
+// rootCause is a likely root cause of the problems of an object. Causes with
+// a higher score are more likely to explain the others, for example a missing
+// Secret explains a CreateContainerConfigError and the failing readiness.
+type rootCause struct {
+	score    int
+	cause    string
+	evidence string
+}
+
+// newExplainOptions creates an instance of ExplainOptions.
+func newExplainOptions(options JanitorOptions) *ExplainOptions {
+	return &ExplainOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newExplainCommand returns a cobra command wrapping ExplainOptions.
+func newExplainCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newExplainOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "explain KIND/NAME",
+		Short:        "Diagnose a single Pod, Job or PersistentVolumeClaim and rank its likely root causes",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, args[0], noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// Run runs the checks applicable to the object and prints its conditions timeline,
+// containers, references, Events, owner chain and ranked likely root causes.
+func (o *ExplainOptions) Run(ctx context.Context, target string, noHeader bool) error {
+	kind, name, err := parseExplainTarget(target)
+	if err != nil {
+		return err
+	}
+
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	var e explanation
+	switch kind {
+	case "Pod":
+		e, err = o.explainPod(ctx, client, name, now)
+	case "Job":
+		e, err = o.explainJob(ctx, client, name, now)
+	case "PersistentVolumeClaim":
+		e, err = o.explainPVC(ctx, client, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	events, err := getEvents(ctx, client, o.namespace, kind, "")
+	if err != nil {
+		return err
+	}
+	objectEvents := events[e.obj.GetUID()]
+	e.causes = append(e.causes, getEventRootCauses(objectEvents)...)
+
+	owners, ownerCauses, err := o.getOwnerChain(ctx, e.obj)
+	if err != nil {
+		return err
+	}
+	e.causes = append(e.causes, ownerCauses...)
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+
+	var conditions [][]string
+	for _, c := range e.conditions {
+		reason, message := c.reason, strings.TrimSpace(c.message)
+		if reason == "" {
+			reason = "<none>"
+		}
+		if message == "" {
+			message = "<none>"
+		}
+		conditions = append(conditions, []string{c.conditionType, string(c.status), reason, message, getAge(c.lastTransitionTime)})
+	}
+
+	var eventRows [][]string
+	for _, event := range objectEvents {
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		lastSeen := duration.HumanDuration(now.Sub(getEventTime(event)))
+		message := strings.Join(strings.Fields(event.Message), " ")
+		eventRows = append(eventRows, []string{event.Type, event.Reason, strconv.Itoa(int(count)), lastSeen, message})
+	}
+
+	var causes [][]string
+	for i, cause := range rankRootCauses(e.causes) {
+		causes = append(causes, []string{strconv.Itoa(i + 1), cause.cause, cause.evidence})
+	}
+
+	return p.printTables(
+		resultTable{name: "conditions", headers: []string{"CONDITION", "STATUS", "REASON", "MESSAGE", "LAST TRANSITION"}, matrix: conditions, optional: true},
+		resultTable{name: "containers", headers: e.containerHeaders, matrix: e.containers, numeric: sets.NewString("EXIT CODE", "RESTARTS", "LAST EXIT CODE"), optional: true},
+		resultTable{name: "references", headers: []string{"REFERENCE", "USED BY", "STATUS"}, matrix: e.references, optional: true},
+		resultTable{name: "events", headers: []string{"TYPE", "REASON", "COUNT", "LAST SEEN", "MESSAGE"}, matrix: eventRows, numeric: sets.NewString("COUNT"), optional: true},
+		resultTable{name: "owners", headers: []string{"OWNER", "STATUS"}, matrix: owners, optional: true},
+		resultTable{name: "rootCauses", headers: []string{"RANK", "ROOT CAUSE", "EVIDENCE"}, matrix: causes, numeric: sets.NewString("RANK")},
+	)
+}
+
+// explainPod diagnoses a Pod from its status, its references and the health of its claims.
+func (o *ExplainOptions) explainPod(ctx context.Context, client kubernetes.Interface, name string, now time.Time) (explanation, error) {
+	pod, err := client.CoreV1().Pods(o.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return explanation{}, err
+	}
+
+	idx, err := getPodRefIndex(ctx, client, o.namespace)
+	if err != nil {
+		return explanation{}, err
+	}
+
+	claimProblems, err := o.getClaimProblems(ctx, client, getPodClaimNames(*pod))
+	if err != nil {
+		return explanation{}, err
+	}
+
+	e := explanation{
+		obj:              pod,
+		conditions:       getPodConditions(*pod),
+		containerHeaders: []string{"CONTAINER", "STATE", "REASON", "EXIT CODE", "RESTARTS", "LAST REASON", "LAST EXIT CODE", "LAST FINISHED"},
+		containers:       getContainerRows(*pod, now),
+		references:       getPodReferenceRows(*pod, idx, claimProblems),
+		causes:           getPodRootCauses(*pod, getPodBrokenRefs(*pod, idx), claimProblems),
+	}
+	return e, nil
+}
+
+// explainJob diagnoses a Job from its conditions and the root causes of its unhealthy Pods.
+func (o *ExplainOptions) explainJob(ctx context.Context, client kubernetes.Interface, name string, now time.Time) (explanation, error) {
+	job, err := client.BatchV1().Jobs(o.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return explanation{}, err
+	}
+
+	var pods []corev1.Pod
+	if job.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return explanation{}, err
+		}
+		list, err := client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return explanation{}, err
+		}
+		pods = list.Items
+	}
+
+	idx, err := getPodRefIndex(ctx, client, o.namespace)
+	if err != nil {
+		return explanation{}, err
+	}
+
+	e := explanation{
+		obj:              job,
+		conditions:       getJobConditions(*job),
+		containerHeaders: []string{"POD", "STATUS", "RESTARTS", "AGE"},
+		causes:           getJobRootCauses(*job, now),
+	}
+
+	for _, pod := range pods {
+		e.containers = append(e.containers, []string{pod.Name, getPodStatus(pod), strconv.Itoa(int(getPodRestarts(pod))), getAge(pod.CreationTimestamp)})
+		if isPodHealthy(pod) {
+			continue
+		}
+
+		claimProblems, err := o.getClaimProblems(ctx, client, getPodClaimNames(pod))
+		if err != nil {
+			return explanation{}, err
+		}
+		for _, cause := range getPodRootCauses(pod, getPodBrokenRefs(pod, idx), claimProblems) {
+			cause.evidence = "pod/" + pod.Name + ": " + cause.evidence
+			e.causes = append(e.causes, cause)
+		}
+	}
+
+	return e, nil
+}
+
+// explainPVC diagnoses a PersistentVolumeClaim with the pending and unused claim checks.
+func (o *ExplainOptions) explainPVC(ctx context.Context, client kubernetes.Interface, name string) (explanation, error) {
+	pvc, err := client.CoreV1().PersistentVolumeClaims(o.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return explanation{}, err
+	}
+
+	pods, err := client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return explanation{}, err
+	}
+
+	e := explanation{
+		obj:              pvc,
+		conditions:       getPVCConditions(*pvc),
+		containerHeaders: []string{"POD", "STATUS", "VOLUME"},
+	}
+
+	consumers := make(map[types.NamespacedName]bool)
+	for _, pod := range pods.Items {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvc.Name {
+				consumers[types.NamespacedName{Namespace: pod.Namespace, Name: pvc.Name}] = true
+				e.containers = append(e.containers, []string{pod.Name, getPodStatus(pod), volume.Name})
+			}
+		}
+	}
+
+	var pendingReasons []string
+	if pvc.Status.Phase == corev1.ClaimPending {
+		pc, err := getPendingPVCContext(ctx, client, o.namespace, o.Streams.ErrOut)
+		if err != nil {
+			return explanation{}, err
+		}
+		// Normal ExternalProvisioning Events tell which provisioner the claim waits for.
+		events, err := getEvents(ctx, client, o.namespace, "PersistentVolumeClaim", "")
+		if err != nil {
+			return explanation{}, err
+		}
+		pendingReasons = getPVCPendingReasons(*pvc, pc, events[pvc.UID])
+	}
+
+	unusedReason := ""
+	if pvc.Status.Phase == corev1.ClaimBound {
+		statefulSets, err := client.AppsV1().StatefulSets(o.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return explanation{}, err
+		}
+		unusedReason, _ = getUnusedPVCReason(*pvc, consumers, statefulSets.Items)
+	}
+
+	e.causes = getPVCRootCauses(*pvc, pendingReasons, unusedReason)
+	return e, nil
+}
+
+// getClaimProblems returns the problem of every claim that is not Bound, by claim name.
+func (o *ExplainOptions) getClaimProblems(ctx context.Context, client kubernetes.Interface, claims []string) (map[string]string, error) {
+	problems := make(map[string]string)
+
+	var pending []corev1.PersistentVolumeClaim
+	for _, claim := range claims {
+		pvc, err := client.CoreV1().PersistentVolumeClaims(o.namespace).Get(ctx, claim, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// Missing claims are reported as broken references.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch pvc.Status.Phase {
+		case corev1.ClaimBound:
+		case corev1.ClaimPending:
+			pending = append(pending, *pvc)
+		default:
+			problems[claim] = string(pvc.Status.Phase)
+		}
+	}
+
+	if len(pending) == 0 {
+		return problems, nil
+	}
+
+	pc, err := getPendingPVCContext(ctx, client, o.namespace, o.Streams.ErrOut)
+	if err != nil {
+		return nil, err
+	}
+	events, err := getEvents(ctx, client, o.namespace, "PersistentVolumeClaim", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pvc := range pending {
+		problem := string(corev1.ClaimPending)
+		if reasons := getPVCPendingReasons(pvc, pc, events[pvc.UID]); len(reasons) > 0 {
+			problem += ": " + strings.Join(reasons, "; ")
+		}
+		problems[pvc.Name] = problem
+	}
+
+	return problems, nil
+}
+
+// getOwnerChain walks the ownerReferences of the object up to its top controller.
+// An owner that no longer exists ends the chain and is reported as a root cause.
+func (o *ExplainOptions) getOwnerChain(ctx context.Context, obj metav1.Object) ([][]string, []rootCause, error) {
+	if len(obj.GetOwnerReferences()) == 0 {
+		return nil, nil, nil
+	}
+
+	discoveryClient, err := o.GetDiscoveryClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, nil, err
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	dynamicClient, err := o.GetDynamicClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows [][]string
+	var causes []rootCause
+
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		owner := metav1.GetControllerOf(obj)
+		if owner == nil {
+			refs := obj.GetOwnerReferences()
+			if len(refs) == 0 {
+				break
+			}
+			owner = &refs[0]
+		}
+
+		ref := strings.ToLower(owner.Kind) + "/" + owner.Name
+
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			rows = append(rows, []string{ref, "Unknown"})
+			break
+		}
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: owner.Kind}, gv.Version)
+		if err != nil {
+			rows = append(rows, []string{ref, "Unknown"})
+			break
+		}
+		ref = mapping.Resource.GroupResource().String() + "/" + owner.Name
+
+		client := dynamicClient.Resource(mapping.Resource)
+		var u *unstructured.Unstructured
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			u, err = client.Namespace(obj.GetNamespace()).Get(ctx, owner.Name, metav1.GetOptions{})
+		} else {
+			u, err = client.Get(ctx, owner.Name, metav1.GetOptions{})
+		}
+		if apierrors.IsNotFound(err) || (err == nil && u.GetUID() != owner.UID) {
+			rows = append(rows, []string{ref, "NotFound"})
+			causes = append(causes, rootCause{30, "OwnerNotFound", fmt.Sprintf("%s (uid %s) no longer exists", ref, owner.UID)})
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rows = append(rows, []string{ref, getOwnerStatus(*u)})
+		obj = u
+	}
+
+	return rows, causes, nil
+}
+
+// parseExplainTarget splits a KIND/NAME argument and resolves the kind.
+func parseExplainTarget(target string) (string, string, error) {
+	parts := strings.SplitN(target, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid object %q: must be KIND/NAME", target)
+	}
+
+	kind, ok := explainKinds[strings.ToLower(parts[0])]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported kind %q: must be one of pod, job or pvc", parts[0])
+	}
+	return kind, parts[1], nil
+}
+
+// getOwnerStatus summarizes the replicas of an owner, when it has any.
+func getOwnerStatus(u unstructured.Unstructured) string {
+	if u.GetDeletionTimestamp() != nil {
+		return "Terminating"
+	}
+
+	replicas, found, _ := unstructured.NestedInt64(u.Object, "status", "replicas")
+	if !found {
+		return "Exists"
+	}
+	ready, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	return fmt.Sprintf("%d/%d ready", ready, replicas)
+}
+
+// getPodConditions returns the conditions of the Pod sorted by transition time.
+func getPodConditions(pod corev1.Pod) []explainCondition {
+	var conditions []explainCondition
+	for _, c := range pod.Status.Conditions {
+		conditions = append(conditions, explainCondition{string(c.Type), c.Status, c.Reason, c.Message, c.LastTransitionTime})
+	}
+	sortExplainConditions(conditions)
+	return conditions
+}
+
+// getJobConditions returns the conditions of the Job sorted by transition time.
+func getJobConditions(job batchv1.Job) []explainCondition {
+	var conditions []explainCondition
+	for _, c := range job.Status.Conditions {
+		conditions = append(conditions, explainCondition{string(c.Type), c.Status, c.Reason, c.Message, c.LastTransitionTime})
+	}
+	sortExplainConditions(conditions)
+	return conditions
+}
+
+// getPVCConditions returns the conditions of the PersistentVolumeClaim sorted by transition time.
+func getPVCConditions(pvc corev1.PersistentVolumeClaim) []explainCondition {
+	var conditions []explainCondition
+	for _, c := range pvc.Status.Conditions {
+		conditions = append(conditions, explainCondition{string(c.Type), c.Status, c.Reason, c.Message, c.LastTransitionTime})
+	}
+	sortExplainConditions(conditions)
+	return conditions
+}
+
+// sortExplainConditions sorts the conditions from the oldest to the latest transition.
+func sortExplainConditions(conditions []explainCondition) {
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return conditions[i].lastTransitionTime.Before(&conditions[j].lastTransitionTime)
+	})
+}
+
+// getPodRestarts returns the number of restarts of the containers of the Pod.
+func getPodRestarts(pod corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
+
+// getContainerRows returns the current and last state of every init and regular container of the Pod.
+func getContainerRows(pod corev1.Pod, now time.Time) [][]string {
+	var rows [][]string
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		state, reason, exitCode := "Unknown", "<none>", "<none>"
+		switch {
+		case status.State.Waiting != nil:
+			state, reason = "Waiting", status.State.Waiting.Reason
+		case status.State.Running != nil:
+			state = "Running"
+		case status.State.Terminated != nil:
+			state, reason = "Terminated", status.State.Terminated.Reason
+			exitCode = strconv.Itoa(int(status.State.Terminated.ExitCode))
+		}
+		if reason == "" {
+			reason = "<none>"
+		}
+
+		lastReason, lastExitCode, lastFinished := "<none>", "<none>", "<none>"
+		if last := status.LastTerminationState.Terminated; last != nil {
+			lastReason = last.Reason
+			lastExitCode = strconv.Itoa(int(last.ExitCode))
+			if !last.FinishedAt.IsZero() {
+				lastFinished = duration.HumanDuration(now.Sub(last.FinishedAt.Time))
+			}
+		}
+
+		rows = append(rows, []string{status.Name, state, reason, exitCode, strconv.Itoa(int(status.RestartCount)), lastReason, lastExitCode, lastFinished})
+	}
+
+	return rows
+}
+
+// getPodReferenceRows returns every PersistentVolumeClaim, ConfigMap and Secret
+// referenced by the Pod with its health.
+func getPodReferenceRows(pod corev1.Pod, idx podRefIndex, claimProblems map[string]string) [][]string {
+	var rows [][]string
+	seen := make(map[string]bool)
+
+	walkPodRefs(pod, func(kind, name, key, usedBy string, optional *bool) {
+		reference := kind + "/" + name
+		if key != "" {
+			reference += "[" + key + "]"
+		}
+
+		status := idx.getProblem(pod.Namespace, kind, name, key)
+		switch {
+		case status == "" && kind == "persistentvolumeclaim" && claimProblems[name] != "":
+			status = claimProblems[name]
+		case status == "":
+			status = "OK"
+		case optional != nil && *optional:
+			status += " (optional)"
+		}
+
+		row := []string{reference, usedBy, status}
+		if key := strings.Join(row, "\t"); !seen[key] {
+			seen[key] = true
+			rows = append(rows, row)
+		}
+	})
+
+	return rows
+}
+
+// getPodRootCauses returns the likely root causes of the problems of a Pod from
+// its broken references, the problems of its claims and its status.
+func getPodRootCauses(pod corev1.Pod, refs []brokenRef, claimProblems map[string]string) []rootCause {
+	var causes []rootCause
+
+	for _, ref := range refs {
+		reference := ref.kind + "/" + ref.name
+		if ref.key != "" {
+			reference += "[" + ref.key + "]"
+		}
+		causes = append(causes, rootCause{100, "MissingReference", fmt.Sprintf("%s used by %s: %s", reference, ref.usedBy, ref.problem)})
+	}
+
+	var claims []string
+	for claim := range claimProblems {
+		claims = append(claims, claim)
+	}
+	sort.Strings(claims)
+	for _, claim := range claims {
+		causes = append(causes, rootCause{95, "ClaimNotBound", fmt.Sprintf("persistentvolumeclaim/%s: %s", claim, claimProblems[claim])})
+	}
+
+	if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason != "" {
+		causes = append(causes, rootCause{90, pod.Status.Reason, strings.TrimSpace(pod.Status.Message)})
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			causes = append(causes, rootCause{90, "Unschedulable", strings.TrimSpace(c.Message)})
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		container := "container/" + status.Name
+
+		if waiting := status.State.Waiting; waiting != nil {
+			evidence := container
+			if message := strings.TrimSpace(waiting.Message); message != "" {
+				evidence += ": " + message
+			}
+
+			switch waiting.Reason {
+			case "CreateContainerConfigError", "CreateContainerError", "InvalidImageName":
+				causes = append(causes, rootCause{85, waiting.Reason, evidence})
+			case "ImagePullBackOff", "ErrImagePull":
+				causes = append(causes, rootCause{80, "ImagePullFailed", evidence})
+			}
+		}
+
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || (terminated.ExitCode == 0 && terminated.Reason != "OOMKilled") {
+			continue
+		}
+
+		reason := terminated.Reason
+		if reason == "" {
+			reason = "Error"
+		}
+		evidence := fmt.Sprintf("%s terminated with %s (exit code %d), %d restarts", container, reason, terminated.ExitCode, status.RestartCount)
+		if reason == "OOMKilled" {
+			causes = append(causes, rootCause{75, "OOMKilled", evidence})
+		} else {
+			causes = append(causes, rootCause{70, "ContainerFailing", evidence})
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodRunning && !isPodReady(pod) {
+		causes = append(causes, rootCause{40, "NotReady", fmt.Sprintf("pod is %s but not ready", getPodStatus(pod))})
+	}
+
+	return causes
+}
+
+// getJobRootCauses returns the likely root causes of the problems of a Job from
+// its Failed condition and the stuck Job check with the defaults of jobs stuck.
+func getJobRootCauses(job batchv1.Job, now time.Time) []rootCause {
+	var causes []rootCause
+
+	if c := getJobFailedCondition(job); c != nil {
+		causes = append(causes, rootCause{90, c.Reason, c.Message})
+	}
+
+	if reason := getJobStuckReason(job, defaultStuckJobAge, defaultStuckJobDeadlineRatio, now); reason != "" {
+		running := duration.HumanDuration(now.Sub(job.Status.StartTime.Time))
+		causes = append(causes, rootCause{60, reason, fmt.Sprintf("running for %s with %d active Pods", running, job.Status.Active)})
+	}
+
+	return causes
+}
+
+// getPVCRootCauses returns the likely root causes of the problems of a
+// PersistentVolumeClaim from the pending and unused claim checks.
+func getPVCRootCauses(pvc corev1.PersistentVolumeClaim, pendingReasons []string, unusedReason string) []rootCause {
+	var causes []rootCause
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimLost:
+		causes = append(causes, rootCause{100, "ClaimLost", fmt.Sprintf("persistentvolume/%s no longer exists", pvc.Spec.VolumeName)})
+	case corev1.ClaimPending:
+		for _, reason := range pendingReasons {
+			causes = append(causes, rootCause{90, "ClaimPending", reason})
+		}
+		if len(pendingReasons) == 0 {
+			causes = append(causes, rootCause{50, "ClaimPending", "no reason found, check the provisioner logs"})
+		}
+	}
+
+	if unusedReason != "" {
+		causes = append(causes, rootCause{30, "ClaimUnused", unusedReason})
+	}
+
+	return causes
+}
+
+// getEventRootCauses returns a root cause for every reason of the Warning Events,
+// scored after the checks so that they only come first when nothing else is found.
+func getEventRootCauses(events []corev1.Event) []rootCause {
+	var causes []rootCause
+	seen := make(map[string]bool)
+
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning || seen[event.Reason] {
+			continue
+		}
+		seen[event.Reason] = true
+
+		score := 20
+		switch event.Reason {
+		case "FailedMount", "FailedAttachVolume":
+			score = 65
+		case "Unhealthy":
+			score = 50
+		}
+		causes = append(causes, rootCause{score, event.Reason, strings.Join(strings.Fields(event.Message), " ")})
+	}
+
+	return causes
+}
+
+// rankRootCauses sorts the root causes by decreasing score and drops duplicates.
+func rankRootCauses(causes []rootCause) []rootCause {
+	var ranked []rootCause
+	seen := make(map[rootCause]bool)
+	for _, cause := range causes {
+		if !seen[cause] {
+			seen[cause] = true
+			ranked = append(ranked, cause)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	return ranked
+}
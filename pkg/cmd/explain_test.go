@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestParseExplainTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		wantKind string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "Pod", target: "pod/web-0", wantKind: "Pod", wantName: "web-0"},
+		{name: "Short Pod", target: "po/web-0", wantKind: "Pod", wantName: "web-0"},
+		{name: "Qualified Job", target: "jobs.batch/backup", wantKind: "Job", wantName: "backup"},
+		{name: "Short PersistentVolumeClaim", target: "PVC/data", wantKind: "PersistentVolumeClaim", wantName: "data"},
+		{name: "Missing name", target: "pod/", wantErr: true},
+		{name: "Missing kind", target: "web-0", wantErr: true},
+		{name: "Unsupported kind", target: "deployment/web", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			kind, name, err := parseExplainTarget(tt.target)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
+func TestGetPodRootCauses(t *testing.T) {
+	tests := []struct {
+		name          string
+		pod           corev1.Pod
+		refs          []brokenRef
+		claimProblems map[string]string
+		want          []rootCause
+	}{
+		{
+			name: "Healthy Pod is expected to have no root cause",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				},
+			},
+		},
+		{
+			name: "Missing Secret is expected to come before the container error it causes",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "app",
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CreateContainerConfigError", Message: `secret "db" not found`}},
+					}},
+				},
+			},
+			refs: []brokenRef{{kind: "secret", name: "db", usedBy: "container/app envFrom", problem: "NotFound"}},
+			want: []rootCause{
+				{100, "MissingReference", "secret/db used by container/app envFrom: NotFound"},
+				{85, "CreateContainerConfigError", `container/app: secret "db" not found`},
+			},
+		},
+		{
+			name: "Pod pending on an unbound claim is expected to report the claim and scheduling",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Message: "0/3 nodes are available: 3 pod has unbound immediate PersistentVolumeClaims.",
+					}},
+				},
+			},
+			claimProblems: map[string]string{"data": "Pending: StorageClassNotFound(fast)"},
+			want: []rootCause{
+				{95, "ClaimNotBound", "persistentvolumeclaim/data: Pending: StorageClassNotFound(fast)"},
+				{90, "Unschedulable", "0/3 nodes are available: 3 pod has unbound immediate PersistentVolumeClaims."},
+			},
+		},
+		{
+			name: "Crash looping container is expected to report the reason and exit code of its last termination",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:                 "app",
+						RestartCount:         7,
+						State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+					}},
+				},
+			},
+			want: []rootCause{
+				{75, "OOMKilled", "container/app terminated with OOMKilled (exit code 137), 7 restarts"},
+				{40, "NotReady", "pod is CrashLoopBackOff but not ready"},
+			},
+		},
+		{
+			name: "Evicted Pod is expected to report the eviction",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:   corev1.PodFailed,
+					Reason:  "Evicted",
+					Message: "The node was low on resource: memory.",
+				},
+			},
+			want: []rootCause{
+				{90, "Evicted", "The node was low on resource: memory."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, rankRootCauses(getPodRootCauses(tt.pod, tt.refs, tt.claimProblems)))
+		})
+	}
+}
+
+func TestGetJobRootCauses(t *testing.T) {
+	now := time.Now()
+	deadline := int64(600)
+
+	tests := []struct {
+		name string
+		job  batchv1.Job
+		want []rootCause
+	}{
+		{
+			name: "Failed Job is expected to report its Failed condition",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{{
+						Type:    batchv1.JobFailed,
+						Status:  corev1.ConditionTrue,
+						Reason:  "BackoffLimitExceeded",
+						Message: "Job has reached the specified backoff limit",
+					}},
+				},
+			},
+			want: []rootCause{{90, "BackoffLimitExceeded", "Job has reached the specified backoff limit"}},
+		},
+		{
+			name: "Active Job close to its deadline is expected to be reported as stuck",
+			job: batchv1.Job{
+				Spec: batchv1.JobSpec{ActiveDeadlineSeconds: &deadline},
+				Status: batchv1.JobStatus{
+					Active:    1,
+					StartTime: &metav1.Time{Time: now.Add(-9 * time.Minute)},
+				},
+			},
+			want: []rootCause{{60, "NearActiveDeadline", "running for 9m with 1 active Pods"}},
+		},
+		{
+			name: "Complete Job is expected to have no root cause",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Succeeded:  1,
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getJobRootCauses(tt.job, now))
+		})
+	}
+}
+
+func TestGetPVCRootCauses(t *testing.T) {
+	tests := []struct {
+		name           string
+		pvc            corev1.PersistentVolumeClaim
+		pendingReasons []string
+		unusedReason   string
+		want           []rootCause
+	}{
+		{
+			name:           "Pending claim is expected to report every pending reason",
+			pvc:            corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+			pendingReasons: []string{"NoDefaultStorageClass", "NoMatchingAvailablePV"},
+			want: []rootCause{
+				{90, "ClaimPending", "NoDefaultStorageClass"},
+				{90, "ClaimPending", "NoMatchingAvailablePV"},
+			},
+		},
+		{
+			name: "Lost claim is expected to report its missing volume",
+			pvc: corev1.PersistentVolumeClaim{
+				Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimLost},
+			},
+			want: []rootCause{{100, "ClaimLost", "persistentvolume/pv-1 no longer exists"}},
+		},
+		{
+			name:         "Unused Bound claim is expected to report why it is unused",
+			pvc:          corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}},
+			unusedReason: "NotMounted",
+			want:         []rootCause{{30, "ClaimUnused", "NotMounted"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getPVCRootCauses(tt.pvc, tt.pendingReasons, tt.unusedReason))
+		})
+	}
+}
+
+func TestGetPodReferenceRows(t *testing.T) {
+	optional := true
+
+	idx := podRefIndex{
+		configMaps: map[types.NamespacedName]map[string]bool{
+			{Namespace: "default", Name: "app-config"}: {"LOG_LEVEL": true},
+		},
+		secrets: map[types.NamespacedName]map[string]bool{},
+		pvcs: map[types.NamespacedName]bool{
+			{Namespace: "default", Name: "data"}: true,
+		},
+	}
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+				{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "tls", Optional: &optional}}},
+			},
+			Containers: []corev1.Container{{
+				Name: "app",
+				Env: []corev1.EnvVar{{
+					Name:      "LEVEL",
+					ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}, Key: "LEVEL"}},
+				}},
+			}},
+		},
+	}
+
+	want := [][]string{
+		{"persistentvolumeclaim/data", "volume/data", "Pending: NoMatchingAvailablePV"},
+		{"configmap/app-config", "volume/config", "OK"},
+		{"secret/tls", "volume/tls", "NotFound (optional)"},
+		{"configmap/app-config[LEVEL]", "container/app env/LEVEL", "KeyNotFound"},
+	}
+
+	assert.Equal(t, want, getPodReferenceRows(pod, idx, map[string]string{"data": "Pending: NoMatchingAvailablePV"}))
+}
+
+func TestExplainRunPendingPVC(t *testing.T) {
+	class := "csi-fast"
+	pvc := newTestPVC(testNamespace, "data", &class, "10Gi")
+	pvc.UID = "data-uid"
+	pvc.Status.Phase = corev1.ClaimPending
+
+	event := corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: testNamespace, Name: "data.1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: testNamespace, Name: "data", UID: pvc.UID},
+		Type:           corev1.EventTypeNormal,
+		Reason:         "ExternalProvisioning",
+		Message:        `waiting for a volume to be created by "csi.example.com"`,
+		LastTimestamp:  metav1.Time{Time: time.Now()},
+	}
+	storageClass := storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: class}, Provisioner: "csi.example.com"}
+
+	options, _, out, _ := newFakeJanitorOptions(withEventFieldSelectors(fake.NewSimpleClientset(&pvc, &event, &storageClass)))
+	o := newExplainOptions(options)
+
+	err := o.Run(context.Background(), "pvc/data", true)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `ClaimPending   ExternalProvisioning: waiting for a volume to be created by "csi.example.com"`)
+}
+
+func TestGetOwnerChain(t *testing.T) {
+	replicaSet := newTestObject(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, testNamespace, "web-abc")
+	deploymentRef := newTestControllerRef("Deployment", "web", "deployment-uid")
+	deploymentRef.APIVersion = "apps/v1"
+	replicaSet.SetOwnerReferences([]metav1.OwnerReference{deploymentRef})
+	assert.NoError(t, unstructured.SetNestedField(replicaSet.Object, int64(2), "status", "replicas"))
+	assert.NoError(t, unstructured.SetNestedField(replicaSet.Object, int64(1), "status", "readyReplicas"))
+
+	replicaSetRef := newTestControllerRef("ReplicaSet", "web-abc", replicaSet.GetUID())
+	replicaSetRef.APIVersion = "apps/v1"
+	pod := newTestPod(testNamespace, "web-abc-1")
+	pod.OwnerReferences = []metav1.OwnerReference{replicaSetRef}
+
+	verbs := []string{"get", "list"}
+	options, _, _, _ := newFakeJanitorOptions(nil)
+	options.dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &replicaSet)
+	options.discoveryClient = &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: verbs},
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: verbs},
+		}},
+	}}}
+	o := newExplainOptions(options)
+
+	owners, causes, err := o.getOwnerChain(context.Background(), &pod)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"replicasets.apps/web-abc", "1/2 ready"},
+		{"deployments.apps/web", "NotFound"},
+	}, owners)
+	assert.Equal(t, []rootCause{{30, "OwnerNotFound", "deployments.apps/web (uid deployment-uid) no longer exists"}}, causes)
+}
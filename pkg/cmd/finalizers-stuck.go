@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// StuckFinalizersOptions embeds JanitorOptions struct.
+type StuckFinalizersOptions struct {
+	JanitorOptions
+	olderThan       time.Duration
+	removeFinalizer string
+	dryRun          bool
+	backupDir       string
+}
+
+// stuckObject is an object being deleted that is still held by a finalizer.
+type stuckObject struct {
+	resource  apiResource
+	obj       unstructured.Unstructured
+	finalizer string
+}
+
+// newStuckFinalizersOptions creates an instance of StuckFinalizersOptions.
+func newStuckFinalizersOptions(options JanitorOptions) *StuckFinalizersOptions {
+	return &StuckFinalizersOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newStuckFinalizersCommand returns a cobra command wrapping StuckFinalizersOptions.
+func newStuckFinalizersCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newStuckFinalizersOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "stuck",
+		Short:        "List objects of any type whose deletion is held by finalizers",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.olderThan, "older-than", 10*time.Minute, "List objects that have been deleting for longer than this duration.")
+	cmd.Flags().StringVar(&o.removeFinalizer, "remove-finalizer", "", "Remove this finalizer from the listed objects, after a backup and confirmation.")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only submit server-side dry-run requests, without persisting any change.")
+	cmd.Flags().StringVar(&o.backupDir, "backup-dir", ".", "Directory where the manifests of the objects are saved before their finalizer is removed.")
+
+	return cmd
+}
+
+// Run lists the objects stuck on finalizers and optionally removes one of them.
+// Cluster-scoped resources are always walked; namespaced ones in the selected Namespaces.
+// The resources that cannot be listed are reported after the stuck objects.
+func (o *StuckFinalizersOptions) Run(ctx context.Context, noHeader bool) error {
+	discoveryClient, err := o.GetDiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	resources, err := getServedResources(discoveryClient, false)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	var stuck []stuckObject
+	var unlisted [][]string
+	seen := make(map[types.UID]bool)
+
+	for _, resource := range resources {
+		client := dynamicClient.Resource(resource.GroupVersionResource)
+
+		var list *unstructured.UnstructuredList
+		if resource.namespaced {
+			list, err = client.Namespace(o.namespace).List(ctx, metav1.ListOptions{})
+		} else {
+			list, err = client.List(ctx, metav1.ListOptions{})
+		}
+		if err != nil {
+			// Resources that cannot be listed, for example because of RBAC or
+			// an unavailable aggregated API, may hold stuck objects, so they
+			// are reported rather than skipped.
+			namespace := "<none>"
+			if resource.namespaced {
+				namespace = o.namespace
+				if namespace == "" {
+					namespace = "<all>"
+				}
+			}
+			unlisted = append(unlisted, []string{getListErrorReason(err), resource.GroupResource().String(), namespace, "<unknown>", "<unknown>"})
+			continue
+		}
+
+		stuck = append(stuck, getStuckObjects(resource, getUnseenObjects(seen, list.Items), o.olderThan, now)...)
+	}
+
+	sortStuckObjects(stuck)
+
+	var matrix [][]string
+	var uids []types.UID
+	var removable []stuckObject
+
+	for _, s := range stuck {
+		namespace := s.obj.GetNamespace()
+		if namespace == "" {
+			namespace = "<none>"
+		}
+
+		deleting := duration.HumanDuration(now.Sub(s.obj.GetDeletionTimestamp().Time))
+		uids = append(uids, s.obj.GetUID())
+		matrix = append(matrix, []string{s.finalizer, s.resource.GroupResource().String(), namespace, s.obj.GetName(), deleting})
+
+		if o.removeFinalizer != "" && s.finalizer == o.removeFinalizer {
+			removable = append(removable, s)
+		}
+	}
+
+	for _, row := range unlisted {
+		uids = append(uids, "")
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"FINALIZER", "RESOURCE", "NAMESPACE", "NAME", "DELETING FOR"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printClusterResults(headers, matrix, uids); err != nil {
+		return err
+	}
+
+	if len(removable) == 0 {
+		return nil
+	}
+
+	var dryRun []string
+	suffix := ""
+	if o.dryRun {
+		dryRun = []string{metav1.DryRunAll}
+		suffix = " (server dry run)"
+	} else {
+		ok, err := confirm(o.Streams.In, o.Streams.ErrOut, fmt.Sprintf("Remove finalizer %s from %d object(s)?", o.removeFinalizer, len(removable)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(o.Streams.ErrOut, "Aborted")
+			return nil
+		}
+	}
+
+	var errs []error
+	for _, s := range removable {
+		ref := s.resource.GroupResource().String() + "/" + s.obj.GetName()
+
+		if !o.dryRun {
+			path, err := backupObject(o.backupDir, s.obj.GetKind(), s.obj.GetNamespace(), s.obj.GetName(), s.obj.Object)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to back up %s: %v", ref, err))
+				continue
+			}
+			fmt.Fprintf(o.Streams.ErrOut, "%s backed up to %s\n", ref, path)
+		}
+
+		patch, err := getRemoveFinalizerPatch(s.obj, s.finalizer)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		client := dynamicClient.Resource(s.resource.GroupVersionResource)
+		if s.resource.namespaced {
+			_, err = client.Namespace(s.obj.GetNamespace()).Patch(ctx, s.obj.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+		} else {
+			_, err = client.Patch(ctx, s.obj.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove finalizer %s from %s: %v", s.finalizer, ref, err))
+			continue
+		}
+		fmt.Fprintf(o.Streams.ErrOut, "%s finalizer %s removed%s\n", ref, s.finalizer, suffix)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// getStuckObjects returns one entry per finalizer of the objects that have been
+// deleting for longer than olderThan.
+func getStuckObjects(resource apiResource, objects []unstructured.Unstructured, olderThan time.Duration, now time.Time) []stuckObject {
+	var stuck []stuckObject
+	for _, obj := range objects {
+		deletion := obj.GetDeletionTimestamp()
+		if deletion == nil || now.Sub(deletion.Time) <= olderThan {
+			continue
+		}
+
+		for _, finalizer := range obj.GetFinalizers() {
+			stuck = append(stuck, stuckObject{resource: resource, obj: obj, finalizer: finalizer})
+		}
+	}
+	return stuck
+}
+
+// sortStuckObjects groups the stuck objects by finalizer, then by resource, Namespace and name.
+func sortStuckObjects(stuck []stuckObject) {
+	sort.SliceStable(stuck, func(i, j int) bool {
+		a, b := stuck[i], stuck[j]
+		if a.finalizer != b.finalizer {
+			return a.finalizer < b.finalizer
+		}
+		if ra, rb := a.resource.GroupResource().String(), b.resource.GroupResource().String(); ra != rb {
+			return ra < rb
+		}
+		if a.obj.GetNamespace() != b.obj.GetNamespace() {
+			return a.obj.GetNamespace() < b.obj.GetNamespace()
+		}
+		return a.obj.GetName() < b.obj.GetName()
+	})
+}
+
+// getRemoveFinalizerPatch returns a JSON patch removing the finalizer from the
+// object. The patch tests the finalizer is still at the same position, so it
+// fails instead of removing another finalizer if the list changed meanwhile.
+func getRemoveFinalizerPatch(obj unstructured.Unstructured, finalizer string) ([]byte, error) {
+	for i, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			continue
+		}
+
+		path := fmt.Sprintf("/metadata/finalizers/%d", i)
+		return json.Marshal([]map[string]interface{}{
+			{"op": "test", "path": path, "value": finalizer},
+			{"op": "remove", "path": path},
+		})
+	}
+	return nil, fmt.Errorf("finalizer %s not found on %s", finalizer, obj.GetName())
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetStuckObjects(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	pvc := schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}
+
+	pvcs := apiResource{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, namespaced: true}
+	widgets := apiResource{GroupVersionResource: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, namespaced: true}
+
+	stuck := append(
+		getStuckObjects(widgets, []unstructured.Unstructured{
+			newTestDeletingObject(widget, testNamespace, "b", now.Add(-time.Hour), "example.com/cleanup"),
+			newTestDeletingObject(widget, testNamespace, "a", now.Add(-time.Hour), "example.com/cleanup", "kubernetes.io/pvc-protection"),
+			newTestDeletingObject(widget, testNamespace, "recent", now.Add(-time.Minute), "example.com/cleanup"),
+		}, 10*time.Minute, now),
+		getStuckObjects(pvcs, []unstructured.Unstructured{
+			newTestDeletingObject(pvc, testNamespace, "data", now.Add(-time.Hour), "kubernetes.io/pvc-protection"),
+			newTestObject(pvc, testNamespace, "alive", "kubernetes.io/pvc-protection"),
+			newTestDeletingObject(pvc, testNamespace, "released", now.Add(-time.Hour)),
+		}, 10*time.Minute, now)...,
+	)
+	sortStuckObjects(stuck)
+
+	var got [][]string
+	for _, s := range stuck {
+		got = append(got, []string{s.finalizer, s.resource.GroupResource().String(), s.obj.GetName()})
+	}
+
+	want := [][]string{
+		{"example.com/cleanup", "widgets.example.com", "a"},
+		{"example.com/cleanup", "widgets.example.com", "b"},
+		{"kubernetes.io/pvc-protection", "persistentvolumeclaims", "data"},
+		{"kubernetes.io/pvc-protection", "widgets.example.com", "a"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestGetRemoveFinalizerPatch(t *testing.T) {
+	obj := newTestObject(schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}, testNamespace, "data", "example.com/cleanup", "kubernetes.io/pvc-protection")
+
+	tests := []struct {
+		name      string
+		finalizer string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "expect a patch testing and removing the finalizer at its position",
+			finalizer: "kubernetes.io/pvc-protection",
+			want:      `[{"op":"test","path":"/metadata/finalizers/1","value":"kubernetes.io/pvc-protection"},{"op":"remove","path":"/metadata/finalizers/1"}]`,
+		},
+		{
+			name:      "expect an error for a finalizer the object does not hold",
+			finalizer: "example.com/other",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			patch, err := getRemoveFinalizerPatch(obj, tt.finalizer)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(patch))
+		})
+	}
+}
+
+func TestStuckFinalizersRunRemove(t *testing.T) {
+	deleted := time.Now().Add(-time.Hour)
+	finalizer := "example.com/cleanup"
+
+	// The same Ingress is served by the extensions and networking.k8s.io groups.
+	ingress := newTestDeletingObject(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, testNamespace, "web", deleted, finalizer)
+	legacyIngress := newTestDeletingObject(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}, testNamespace, "web", deleted, finalizer)
+	widgetKind := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	widget := newTestDeletingObject(widgetKind, testNamespace, "a", deleted, finalizer)
+	failing := newTestDeletingObject(widgetKind, testNamespace, "b", deleted, finalizer)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &ingress, &legacyIngress, &widget, &failing)
+	dynamicClient.PrependReactor("patch", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.PatchAction).GetName() == failing.GetName() {
+			return true, nil, errors.New("admission webhook denied the request")
+		}
+		return false, nil, nil
+	})
+
+	verbs := []string{"list", "patch"}
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true, Verbs: verbs}}},
+		{GroupVersion: "extensions/v1beta1", APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true, Verbs: verbs}}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: verbs}}},
+	}}}
+
+	options, in, out, errOut := newFakeJanitorOptions(nil)
+	options.dynamicClient = dynamicClient
+	options.discoveryClient = discoveryClient
+	in.WriteString("y\n")
+
+	o := newStuckFinalizersOptions(options)
+	o.olderThan = 10 * time.Minute
+	o.removeFinalizer = finalizer
+	o.backupDir = t.TempDir()
+
+	err := o.Run(context.Background(), true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "widgets.example.com/b")
+
+	assert.Equal(t, 3, strings.Count(out.String(), finalizer))
+	assert.Contains(t, errOut.String(), "widgets.example.com/a finalizer example.com/cleanup removed")
+
+	// The Ingress is patched once, through whichever group was walked first.
+	var patched []string
+	for _, action := range dynamicClient.Actions() {
+		if patch, ok := action.(k8stesting.PatchAction); ok {
+			patched = append(patched, patch.GetResource().Resource+"/"+patch.GetName())
+		}
+	}
+	assert.ElementsMatch(t, []string{"ingresses/web", "widgets/a", "widgets/b"}, patched)
+
+	got, err := dynamicClient.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).Namespace(testNamespace).Get(context.Background(), widget.GetName(), metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, got.GetFinalizers())
+}
+
+func TestStuckFinalizersRunUnlistable(t *testing.T) {
+	deleted := time.Now().Add(-time.Hour)
+	pvc := newTestDeletingObject(schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}, testNamespace, "data", deleted, "kubernetes.io/pvc-protection")
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &pvc)
+	dynamicClient.PrependReactor("list", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(action.GetResource().GroupResource(), "", nil)
+	})
+
+	verbs := []string{"list"}
+	options, _, out, _ := newFakeJanitorOptions(nil)
+	options.dynamicClient = dynamicClient
+	options.discoveryClient = &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "persistentvolumeclaims", Kind: "PersistentVolumeClaim", Namespaced: true, Verbs: verbs}}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: verbs}}},
+	}}}
+
+	o := newStuckFinalizersOptions(options)
+	o.olderThan = 10 * time.Minute
+
+	err := o.Run(context.Background(), true)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []string{"kubernetes.io/pvc-protection", "persistentvolumeclaims", testNamespace, "data"}, strings.Fields(lines[0])[:4])
+	assert.Equal(t, []string{"<cannot", "list:", "Forbidden>", "widgets.example.com", testNamespace, "<unknown>", "<unknown>"}, strings.Fields(lines[1]))
+}
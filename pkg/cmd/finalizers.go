@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newFinalizersCommand provides the base command when called without any subcommands.
+func newFinalizersCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "finalizers",
+		Short:        "Find objects held by their finalizers",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newStuckFinalizersCommand(factory, options))
+
+	return cmd
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bytes"
+	"time"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testNamespace is the Namespace of the objects built by the test fixtures.
+const testNamespace = "default"
+
+// newFakeJanitorOptions returns JanitorOptions running the commands in the
+// test Namespace against the given client, with their streams captured.
+func newFakeJanitorOptions(client kubernetes.Interface) (JanitorOptions, *bytes.Buffer, *bytes.Buffer, *bytes.Buffer) {
+	o, in, out, errOut := NewTestJanitorOptions()
+	o.client = client
+	o.namespace = testNamespace
+	return o, in, out, errOut
+}
+
+// podOption sets a field of the Pods built by newTestPod.
+type podOption func(*corev1.Pod)
+
+// newTestPod returns a running Pod with a single container named app.
+func newTestPod(namespace, name string, options ...podOption) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	for _, option := range options {
+		option(&pod)
+	}
+	return pod
+}
+
+// withLabels sets the labels of the Pod.
+func withLabels(labels map[string]string) podOption {
+	return func(pod *corev1.Pod) { pod.Labels = labels }
+}
+
+// onNode schedules the Pod on the Node.
+func onNode(node string) podOption {
+	return func(pod *corev1.Pod) { pod.Spec.NodeName = node }
+}
+
+// inPhase sets the phase of the Pod.
+func inPhase(phase corev1.PodPhase) podOption {
+	return func(pod *corev1.Pod) { pod.Status.Phase = phase }
+}
+
+// ownedBy makes the Pod controlled by an object of the kind with the same name.
+func ownedBy(kind string) podOption {
+	return func(pod *corev1.Pod) {
+		pod.OwnerReferences = []metav1.OwnerReference{newTestControllerRef(kind, pod.Name, "")}
+	}
+}
+
+// withRequests sets the resource requests of every container of the Pod.
+func withRequests(requests corev1.ResourceList) podOption {
+	return func(pod *corev1.Pod) {
+		for i := range pod.Spec.Containers {
+			pod.Spec.Containers[i].Resources.Requests = requests
+		}
+	}
+}
+
+// withContainers replaces the containers of the Pod.
+func withContainers(containers ...corev1.Container) podOption {
+	return func(pod *corev1.Pod) { pod.Spec.Containers = containers }
+}
+
+// withSpec replaces the spec of the Pod.
+func withSpec(spec corev1.PodSpec) podOption {
+	return func(pod *corev1.Pod) { pod.Spec = spec }
+}
+
+// withStatus replaces the status of the Pod.
+func withStatus(status corev1.PodStatus) podOption {
+	return func(pod *corev1.Pod) { pod.Status = status }
+}
+
+// newTestControllerRef returns a reference to the controller of an object.
+func newTestControllerRef(kind, name string, uid types.UID) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{Kind: kind, Name: name, UID: uid, Controller: &controller}
+}
+
+// newTestResources returns a list of CPU and memory quantities, leaving out the empty ones.
+func newTestResources(cpu, memory string) corev1.ResourceList {
+	resources := corev1.ResourceList{}
+	if cpu != "" {
+		resources[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		resources[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return resources
+}
+
+// newTestNode returns a Node with the allocatable resources and conditions.
+func newTestNode(name string, allocatable corev1.ResourceList, conditions ...corev1.NodeCondition) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{Allocatable: allocatable, Conditions: conditions},
+	}
+}
+
+// newTestService returns a Service selecting the Pods labeled app=name and
+// forwarding port 80 to the targetPort.
+func newTestService(namespace, name string, targetPort intstr.IntOrString) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: targetPort}},
+		},
+	}
+}
+
+// newTestPVC returns a ReadWriteOnce claim requesting size in the StorageClass.
+func newTestPVC(namespace, name string, class *string, size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: class,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+// newTestPV returns a ReadWriteOnce PersistentVolume of the StorageClass.
+func newTestPV(name, class, capacity string) corev1.PersistentVolume {
+	return corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: class,
+			Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		},
+	}
+}
+
+// newTestCompletedJob returns a Job created at the given time that completed a minute later.
+func newTestCompletedJob(namespace, name string, created time.Time) batchv1.Job {
+	completed := metav1.NewTime(created.Add(time.Minute))
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Status: batchv1.JobStatus{
+			Succeeded:      1,
+			CompletionTime: &completed,
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// newTestFailedJob returns a Job created at the given time that failed.
+func newTestFailedJob(namespace, name string, created time.Time) batchv1.Job {
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Status: batchv1.JobStatus{
+			Failed:     1,
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// newTestPDB returns a PodDisruptionBudget selecting the Pods with the labels.
+func newTestPDB(name string, selector map[string]string, disruptionsAllowed int32) policyv1beta1.PodDisruptionBudget {
+	return policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec:       policyv1beta1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+		Status:     policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+// newTestHPA returns a HorizontalPodAutoscaler of up to 5 replicas scaling the
+// target of the kind and name on its CPU utilization.
+func newTestHPA(kind, name string) autoscalingv2beta2.HorizontalPodAutoscaler {
+	utilization := int32(80)
+	return autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{Kind: kind, Name: name},
+			MaxReplicas:    5,
+			Metrics: []autoscalingv2beta2.MetricSpec{{
+				Type: autoscalingv2beta2.ResourceMetricSourceType,
+				Resource: &autoscalingv2beta2.ResourceMetricSource{
+					Name:   corev1.ResourceCPU,
+					Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: &utilization},
+				},
+			}},
+		},
+		Status: autoscalingv2beta2.HorizontalPodAutoscalerStatus{CurrentReplicas: 2},
+	}
+}
+
+// newTestIngress returns an Ingress routing example.com/api to the backend.
+func newTestIngress(class *string, backend networkingv1.IngressServiceBackend, tls ...networkingv1.IngressTLS) networkingv1.Ingress {
+	return networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "web"},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: class,
+			TLS:              tls,
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:    "/api",
+							Backend: networkingv1.IngressBackend{Service: &backend},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// newTestObject returns an object of any resource holding the finalizers.
+func newTestObject(gvk schema.GroupVersionKind, namespace, name string, finalizers ...string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID(namespace + "/" + name))
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+// newTestDeletingObject returns an object deleted at the given time that is still held by the finalizers.
+func newTestDeletingObject(gvk schema.GroupVersionKind, namespace, name string, deleted time.Time, finalizers ...string) unstructured.Unstructured {
+	obj := newTestObject(gvk, namespace, name, finalizers...)
+	obj.SetDeletionTimestamp(&metav1.Time{Time: deleted})
+	return obj
+}
+
+// newTestWarningEvent returns a warning Event last seen at the given time, a minute after it was first seen.
+func newTestWarningEvent(reason, kind, namespace, name string, count int, lastSeen time.Time) warningEvent {
+	return warningEvent{
+		namespace: namespace,
+		reason:    reason,
+		kind:      kind,
+		name:      name,
+		count:     count,
+		firstSeen: lastSeen.Add(-time.Minute),
+		lastSeen:  lastSeen,
+	}
+}
+
+// stringPtr returns a pointer to the string.
+func stringPtr(s string) *string {
+	return &s
+}
+
+// withEventFieldSelectors makes the fake clientset honor the field selectors of
+// Event lists, which it otherwise ignores.
+func withEventFieldSelectors(client *fake.Clientset) *fake.Clientset {
+	client.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := action.(k8stesting.ListAction)
+		obj, err := client.Tracker().List(corev1.SchemeGroupVersion.WithResource("events"), corev1.SchemeGroupVersion.WithKind("Event"), list.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+
+		events := obj.(*corev1.EventList)
+		selector := list.GetListRestrictions().Fields
+		filtered := &corev1.EventList{ListMeta: events.ListMeta}
+		for _, event := range events.Items {
+			if selector.Matches(fields.Set{"involvedObject.kind": event.InvolvedObject.Kind, "type": event.Type}) {
+				filtered.Items = append(filtered.Items, event)
+			}
+		}
+		return true, filtered, nil
+	})
+	return client
+}
@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hint is an entry of the remediation hints catalog. It applies to a status
+// reason, optionally restricted to an exit code and to a message matching a
+// regular expression. The commands may use the {namespace}, {name} and
+// {container} placeholders.
+type hint struct {
+	Reason      string   `json:"reason"`
+	ExitCode    *int32   `json:"exitCode,omitempty"`
+	Message     string   `json:"message,omitempty"`
+	Explanation string   `json:"explanation"`
+	Commands    []string `json:"commands,omitempty"`
+	message     *regexp.Regexp
+}
+
+// hintContext is a reason reported for a Pod or one of its containers, with
+// the context the hints are matched against.
+type hintContext struct {
+	reason    string
+	exitCode  *int32
+	message   string
+	container string
+}
+
+// podHint is a hint matching a Pod, with its commands ready to run.
+type podHint struct {
+	Reason      string   `json:"reason"`
+	Container   string   `json:"container,omitempty"`
+	Explanation string   `json:"explanation"`
+	Commands    []string `json:"commands"`
+}
+
+// builtinHints is the built-in remediation hints catalog. For every reason the
+// entries restricted to an exit code or message come before the generic one,
+// since only the first matching entry is used. The message of ImagePullBackOff
+// only names the image, so it is matched against the latest pull failure of the
+// container, which ErrImagePull reports directly.
+var builtinHints = []hint{
+	{
+		Reason:      "ImagePullBackOff",
+		Message:     `(?i)not found|manifest unknown|does not exist`,
+		Explanation: "The image or its tag does not exist in the registry.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.containers[*].image}'"},
+	},
+	{
+		Reason:      "ImagePullBackOff",
+		Message:     `(?i)unauthorized|authentication required|denied|forbidden`,
+		Explanation: "The registry refused the credentials, check the imagePullSecrets of the Pod and its ServiceAccount.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.imagePullSecrets}'", "kubectl janitor pods broken-refs -n {namespace}"},
+	},
+	{
+		Reason:      "ImagePullBackOff",
+		Explanation: "The image cannot be pulled and the kubelet is backing off, check the image name and the registry reachability from the node.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "ErrImagePull",
+		Message:     `(?i)not found|manifest unknown|does not exist`,
+		Explanation: "The image or its tag does not exist in the registry.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.containers[*].image}'"},
+	},
+	{
+		Reason:      "ErrImagePull",
+		Message:     `(?i)unauthorized|authentication required|denied|forbidden`,
+		Explanation: "The registry refused the credentials, check the imagePullSecrets of the Pod and its ServiceAccount.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.imagePullSecrets}'", "kubectl janitor pods broken-refs -n {namespace}"},
+	},
+	{
+		Reason:      "ErrImagePull",
+		Explanation: "The last pull of the image failed, the kubelet will retry with a back-off.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "InvalidImageName",
+		Explanation: "The image reference of the container is malformed.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.containers[*].image}'"},
+	},
+	{
+		Reason:      "CreateContainerConfigError",
+		Message:     `(?i)(secret|configmap) .* not found|couldn't find key`,
+		Explanation: "The container references a ConfigMap, Secret or key that does not exist.",
+		Commands:    []string{"kubectl janitor pods broken-refs -n {namespace}"},
+	},
+	{
+		Reason:      "CreateContainerConfigError",
+		Explanation: "The configuration of the container is invalid, for example a runAsNonRoot image running as root.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "CreateContainerError",
+		Explanation: "The container runtime failed to create the container, often because of a name conflict or an invalid mount.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "CrashLoopBackOff",
+		ExitCode:    int32Ptr(137),
+		Explanation: "The container was killed with SIGKILL, usually by the OOM killer: raise its memory limit or reduce its memory usage.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.status.containerStatuses[?(@.name==\"{container}\")].lastState}'", "kubectl top pod {name} -n {namespace} --containers"},
+	},
+	{
+		Reason:      "CrashLoopBackOff",
+		ExitCode:    int32Ptr(127),
+		Explanation: "The command of the container was not found in the image.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.containers[?(@.name==\"{container}\")].command}'"},
+	},
+	{
+		Reason:      "CrashLoopBackOff",
+		ExitCode:    int32Ptr(126),
+		Explanation: "The command of the container is not executable.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.containers[?(@.name==\"{container}\")].command}'"},
+	},
+	{
+		Reason:      "CrashLoopBackOff",
+		ExitCode:    int32Ptr(0),
+		Explanation: "The container exits successfully but the restartPolicy restarts it: it should run a long-lived process, or be a Job.",
+		Commands:    []string{"kubectl logs {name} -n {namespace} -c {container} --previous"},
+	},
+	{
+		Reason:      "CrashLoopBackOff",
+		Explanation: "The container keeps exiting and the kubelet is backing off its restarts, its previous logs usually tell why.",
+		Commands:    []string{"kubectl logs {name} -n {namespace} -c {container} --previous", "kubectl janitor pods unhealthy -n {namespace} --logs"},
+	},
+	{
+		Reason:      "OOMKilled",
+		Explanation: "The container used more memory than its limit: raise the limit or reduce its memory usage.",
+		Commands:    []string{"kubectl top pod {name} -n {namespace} --containers"},
+	},
+	{
+		Reason:      "Error",
+		ExitCode:    int32Ptr(143),
+		Explanation: "The container was stopped with SIGTERM and did not exit cleanly.",
+		Commands:    []string{"kubectl logs {name} -n {namespace} -c {container}"},
+	},
+	{
+		Reason:      "Error",
+		Explanation: "The container exited with a non-zero exit code.",
+		Commands:    []string{"kubectl logs {name} -n {namespace} -c {container}"},
+	},
+	{
+		Reason:      "ContainerCannotRun",
+		Explanation: "The container runtime could not start the command of the container.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "Init:Error",
+		Explanation: "An init container failed, the regular containers will not start until it succeeds.",
+		Commands:    []string{"kubectl logs {name} -n {namespace} -c {container}"},
+	},
+	{
+		Reason:      "Init:CrashLoopBackOff",
+		Explanation: "An init container keeps failing, the regular containers will not start until it succeeds.",
+		Commands:    []string{"kubectl logs {name} -n {namespace} -c {container} --previous"},
+	},
+	{
+		Reason:      "Evicted",
+		Explanation: "The kubelet evicted the Pod because the node was under resource pressure.",
+		Commands:    []string{"kubectl janitor nodes unhealthy", "kubectl delete pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Message:     `(?i)unbound immediate PersistentVolumeClaims`,
+		Explanation: "A PersistentVolumeClaim of the Pod is not bound.",
+		Commands:    []string{"kubectl janitor pvcs pending -n {namespace}"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Message:     `(?i)insufficient (cpu|memory|ephemeral-storage|pods|[a-z0-9.-]+/[a-z0-9.-]+)`,
+		Explanation: "No node has enough allocatable resources left for the requests of the Pod: reduce the requests or add capacity.",
+		Commands:    []string{"kubectl describe nodes | grep -A 8 'Allocated resources'"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Message:     `(?i)taint|didn't tolerate`,
+		Explanation: "The nodes have taints the Pod does not tolerate.",
+		Commands:    []string{"kubectl get nodes -o custom-columns=NAME:.metadata.name,TAINTS:.spec.taints"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Message:     `(?i)node selector|node affinity|nodeaffinity`,
+		Explanation: "No node matches the nodeSelector or node affinity of the Pod.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.nodeSelector}{.spec.affinity.nodeAffinity}'", "kubectl get nodes --show-labels"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Message:     `(?i)pod affinity|anti-affinity`,
+		Explanation: "The Pod affinity or anti-affinity rules cannot be satisfied by any node.",
+		Commands:    []string{"kubectl get pod {name} -n {namespace} -o jsonpath='{.spec.affinity}'"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Message:     `(?i)free ports`,
+		Explanation: "The hostPort of the Pod is already used on every suitable node.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+	{
+		Reason:      "Unschedulable",
+		Explanation: "The scheduler cannot find a node for the Pod.",
+		Commands:    []string{"kubectl describe pod {name} -n {namespace}"},
+	},
+}
+
+// int32Ptr returns a pointer to the value.
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+// newHintCatalog returns the custom hints followed by the built-in ones, with
+// their message patterns compiled.
+func newHintCatalog(custom []hint) ([]hint, error) {
+	catalog := append(append([]hint{}, custom...), builtinHints...)
+	for i := range catalog {
+		if catalog[i].Reason == "" {
+			return nil, fmt.Errorf("invalid hint %d: reason is required", i+1)
+		}
+		if catalog[i].Message == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(catalog[i].Message)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hint message %q for reason %s: %v", catalog[i].Message, catalog[i].Reason, err)
+		}
+		catalog[i].message = re
+	}
+	return catalog, nil
+}
+
+// getHintCatalog returns the remediation hints catalog extended with the hints of the janitor config.
+func (o *JanitorOptions) getHintCatalog() ([]hint, error) {
+	config, err := loadJanitorConfig(o.configPath)
+	if err != nil {
+		return nil, err
+	}
+	return newHintCatalog(config.Hints)
+}
+
+// getHintContexts returns the reasons reported for the Pod and its containers.
+// The reasons of init containers are prefixed with Init: as kubectl does. The
+// events are the Events of the Pod, sorted from newest to oldest.
+func getHintContexts(pod corev1.Pod, events []corev1.Event) []hintContext {
+	var contexts []hintContext
+
+	if pod.Status.Reason != "" {
+		contexts = append(contexts, hintContext{reason: pod.Status.Reason, message: pod.Status.Message})
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason != "" {
+			contexts = append(contexts, hintContext{reason: c.Reason, message: c.Message})
+		}
+	}
+
+	add := func(status corev1.ContainerStatus, prefix, fieldPath string) {
+		switch {
+		case status.State.Waiting != nil:
+			waiting := status.State.Waiting
+			if waiting.Reason == "" || waiting.Reason == "ContainerCreating" || waiting.Reason == "PodInitializing" {
+				return
+			}
+
+			context := hintContext{reason: prefix + waiting.Reason, message: waiting.Message, container: status.Name}
+			if waiting.Reason == "ImagePullBackOff" {
+				if message, ok := getImagePullFailure(events, fmt.Sprintf("%s{%s}", fieldPath, status.Name)); ok {
+					context.message = message
+				}
+			}
+			if last := status.LastTerminationState.Terminated; last != nil {
+				exitCode := last.ExitCode
+				context.exitCode = &exitCode
+			}
+			contexts = append(contexts, context)
+		case status.State.Terminated != nil:
+			terminated := status.State.Terminated
+			if terminated.ExitCode == 0 && terminated.Reason != "OOMKilled" {
+				return
+			}
+
+			exitCode := terminated.ExitCode
+			contexts = append(contexts, hintContext{reason: prefix + terminated.Reason, exitCode: &exitCode, message: terminated.Message, container: status.Name})
+		}
+	}
+
+	for _, status := range pod.Status.InitContainerStatuses {
+		add(status, "Init:", "spec.initContainers")
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		add(status, "", "spec.containers")
+	}
+
+	return contexts
+}
+
+// getImagePullFailure returns the message of the latest failed image pull of
+// the container at the field path, which holds the error of the registry.
+func getImagePullFailure(events []corev1.Event, fieldPath string) (string, bool) {
+	for _, event := range events {
+		if event.Reason == "Failed" && event.InvolvedObject.FieldPath == fieldPath && strings.HasPrefix(event.Message, "Failed to pull image") {
+			return event.Message, true
+		}
+	}
+	return "", false
+}
+
+// getPodHints returns the first matching hint of the catalog for every reason
+// reported for the Pod, with the placeholders of their commands replaced. The
+// events are the Events of the Pod, sorted from newest to oldest.
+func getPodHints(pod corev1.Pod, events []corev1.Event, catalog []hint) []podHint {
+	hints := []podHint{}
+	for _, context := range getHintContexts(pod, events) {
+		h, ok := matchHint(context, catalog)
+		if !ok {
+			continue
+		}
+
+		container := context.container
+		if container == "" {
+			container = "<container>"
+		}
+		replacer := strings.NewReplacer("{namespace}", pod.Namespace, "{name}", pod.Name, "{container}", container)
+
+		commands := []string{}
+		for _, command := range h.Commands {
+			commands = append(commands, replacer.Replace(command))
+		}
+		hints = append(hints, podHint{Reason: context.reason, Container: context.container, Explanation: h.Explanation, Commands: commands})
+	}
+	return hints
+}
+
+// matchHint returns the first hint of the catalog matching the context.
+func matchHint(context hintContext, catalog []hint) (hint, bool) {
+	for _, h := range catalog {
+		if h.Reason != context.reason {
+			continue
+		}
+		if h.ExitCode != nil && (context.exitCode == nil || *h.ExitCode != *context.exitCode) {
+			continue
+		}
+		if h.message != nil && !h.message.MatchString(context.message) {
+			continue
+		}
+		return h, true
+	}
+	return hint{}, false
+}
+
+// getPodHintsLines returns the lines shown under a Pod in the table formats.
+func getPodHintsLines(hints []podHint) []string {
+	var lines []string
+	for _, h := range hints {
+		subject := h.Reason
+		if h.Container != "" {
+			subject += " (container " + h.Container + ")"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", subject, h.Explanation))
+		for _, command := range h.Commands {
+			lines = append(lines, "  $ "+command)
+		}
+	}
+	return lines
+}
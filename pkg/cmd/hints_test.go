@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetPodHints(t *testing.T) {
+	builtin, err := newHintCatalog(nil)
+	assert.NoError(t, err)
+
+	custom, err := newHintCatalog([]hint{{
+		Reason:      "CrashLoopBackOff",
+		ExitCode:    int32Ptr(3),
+		Explanation: "The database migration failed.",
+		Commands:    []string{"kubectl -n {namespace} logs {name} -c {container} --previous | grep migrate"},
+	}})
+	assert.NoError(t, err)
+
+	crashing := func(exitCode int32) corev1.PodStatus {
+		return corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "app",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: exitCode}},
+			}},
+		}
+	}
+
+	// The messages are the ones of the kubelet with containerd.
+	backOff := `Back-off pulling image "registry.example.com/web:v2"`
+	missingImage := `rpc error: code = NotFound desc = failed to pull and unpack image "registry.example.com/web:v2": failed to resolve reference "registry.example.com/web:v2": registry.example.com/web:v2: not found`
+	unauthorized := `rpc error: code = Unknown desc = failed to pull and unpack image "registry.example.com/web:v2": failed to resolve reference "registry.example.com/web:v2": pulling from host registry.example.com failed with status code [manifests v2]: 401 Unauthorized`
+
+	pullingImage := func(reason, message string) corev1.PodStatus {
+		return corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}},
+			}},
+		}
+	}
+	pullFailed := func(message string) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-0", FieldPath: "spec.containers{app}"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Failed",
+			Message:        `Failed to pull image "registry.example.com/web:v2": ` + message,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pod     corev1.Pod
+		events  []corev1.Event
+		catalog []hint
+		want    []podHint
+	}{
+		{
+			name:    "image pull failure is expected to be matched on its message",
+			pod:     newTestPod("shop", "web-0", withStatus(pullingImage("ErrImagePull", missingImage))),
+			catalog: builtin,
+			want: []podHint{{
+				Reason:      "ErrImagePull",
+				Container:   "app",
+				Explanation: "The image or its tag does not exist in the registry.",
+				Commands:    []string{"kubectl get pod web-0 -n shop -o jsonpath='{.spec.containers[*].image}'"},
+			}},
+		},
+		{
+			name:    "image pull back-off is expected to be matched on the latest failed pull",
+			pod:     newTestPod("shop", "web-0", withStatus(pullingImage("ImagePullBackOff", backOff))),
+			events:  []corev1.Event{pullFailed(unauthorized), pullFailed(missingImage)},
+			catalog: builtin,
+			want: []podHint{{
+				Reason:      "ImagePullBackOff",
+				Container:   "app",
+				Explanation: "The registry refused the credentials, check the imagePullSecrets of the Pod and its ServiceAccount.",
+				Commands:    []string{"kubectl get pod web-0 -n shop -o jsonpath='{.spec.imagePullSecrets}'", "kubectl janitor pods broken-refs -n shop"},
+			}},
+		},
+		{
+			name:    "image pull back-off without failed pull is expected to fall back to the generic hint",
+			pod:     newTestPod("shop", "web-0", withStatus(pullingImage("ImagePullBackOff", backOff))),
+			catalog: builtin,
+			want: []podHint{{
+				Reason:      "ImagePullBackOff",
+				Container:   "app",
+				Explanation: "The image cannot be pulled and the kubelet is backing off, check the image name and the registry reachability from the node.",
+				Commands:    []string{"kubectl describe pod web-0 -n shop"},
+			}},
+		},
+		{
+			name:    "crash loop killed by SIGKILL is expected to be matched on its exit code",
+			pod:     newTestPod("shop", "web-0", withStatus(crashing(137))),
+			catalog: builtin,
+			want: []podHint{{
+				Reason:      "CrashLoopBackOff",
+				Container:   "app",
+				Explanation: "The container was killed with SIGKILL, usually by the OOM killer: raise its memory limit or reduce its memory usage.",
+				Commands: []string{
+					"kubectl get pod web-0 -n shop -o jsonpath='{.status.containerStatuses[?(@.name==\"app\")].lastState}'",
+					"kubectl top pod web-0 -n shop --containers",
+				},
+			}},
+		},
+		{
+			name:    "custom hint is expected to take precedence over the built-in ones",
+			pod:     newTestPod("shop", "web-0", withStatus(crashing(3))),
+			catalog: custom,
+			want: []podHint{{
+				Reason:      "CrashLoopBackOff",
+				Container:   "app",
+				Explanation: "The database migration failed.",
+				Commands:    []string{"kubectl -n shop logs web-0 -c app --previous | grep migrate"},
+			}},
+		},
+		{
+			name:    "other exit codes are expected to fall back to the generic hint",
+			pod:     newTestPod("shop", "web-0", withStatus(crashing(1))),
+			catalog: custom,
+			want: []podHint{{
+				Reason:      "CrashLoopBackOff",
+				Container:   "app",
+				Explanation: "The container keeps exiting and the kubelet is backing off its restarts, its previous logs usually tell why.",
+				Commands:    []string{"kubectl logs web-0 -n shop -c app --previous", "kubectl janitor pods unhealthy -n shop --logs"},
+			}},
+		},
+		{
+			name: "failed init container is expected to be prefixed with Init",
+			pod: newTestPod("shop", "web-0", withStatus(corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "migrate",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+				}},
+			})),
+			catalog: builtin,
+			want: []podHint{{
+				Reason:      "Init:Error",
+				Container:   "migrate",
+				Explanation: "An init container failed, the regular containers will not start until it succeeds.",
+				Commands:    []string{"kubectl logs web-0 -n shop -c migrate"},
+			}},
+		},
+		{
+			name: "unschedulable Pod is expected to be matched on the scheduler message",
+			pod: newTestPod("shop", "web-0", withStatus(corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: "0/3 nodes are available: 3 Insufficient memory.",
+				}},
+			})),
+			catalog: builtin,
+			want: []podHint{{
+				Reason:      "Unschedulable",
+				Explanation: "No node has enough allocatable resources left for the requests of the Pod: reduce the requests or add capacity.",
+				Commands:    []string{"kubectl describe nodes | grep -A 8 'Allocated resources'"},
+			}},
+		},
+		{
+			name: "healthy Pod is expected to have no hint",
+			pod: newTestPod("shop", "web-0", withStatus(corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "app",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			})),
+			catalog: builtin,
+			want:    []podHint{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getPodHints(tt.pod, tt.events, tt.catalog))
+		})
+	}
+}
+
+func TestNewHintCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		custom  []hint
+		wantErr bool
+	}{
+		{name: "built-in catalog is expected to be valid"},
+		{name: "custom hint without reason is expected to be rejected", custom: []hint{{Explanation: "x"}}, wantErr: true},
+		{name: "custom hint with an invalid pattern is expected to be rejected", custom: []hint{{Reason: "Error", Message: "("}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			catalog, err := newHintCatalog(tt.custom)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, catalog, len(builtinHints))
+		})
+	}
+}
+
+func TestGetPodHintsLines(t *testing.T) {
+	hints := []podHint{
+		{Reason: "Evicted", Explanation: "The node was under pressure.", Commands: []string{"kubectl janitor nodes unhealthy"}},
+		{Reason: "Error", Container: "app", Explanation: "The container failed.", Commands: []string{}},
+	}
+
+	want := []string{
+		"Evicted: The node was under pressure.",
+		"  $ kubectl janitor nodes unhealthy",
+		"Error (container app): The container failed.",
+	}
+	assert.Equal(t, want, getPodHintsLines(hints))
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// UnhealthyHPAsOptions embeds JanitorOptions struct.
+type UnhealthyHPAsOptions struct {
+	JanitorOptions
+	maxedFor time.Duration
+}
+
+// hpaTargetKey identifies the workload scaled by a HorizontalPodAutoscaler.
+type hpaTargetKey struct {
+	kind string
+	types.NamespacedName
+}
+
+// newUnhealthyHPAsOptions creates an instance of UnhealthyHPAsOptions.
+func newUnhealthyHPAsOptions(options JanitorOptions) *UnhealthyHPAsOptions {
+	return &UnhealthyHPAsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newUnhealthyHPAsCommand returns a cobra command wrapping UnhealthyHPAsOptions.
+func newUnhealthyHPAsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newUnhealthyHPAsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "unhealthy",
+		Short:        "List HorizontalPodAutoscalers that cannot scale, are pinned at maxReplicas or target missing workloads",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.maxedFor, "maxed-for", time.Hour, "List HorizontalPodAutoscalers that have been running at maxReplicas for longer than this duration.")
+
+	return cmd
+}
+
+// Run lists HorizontalPodAutoscalers in an unhealthy state.
+func (o *UnhealthyHPAsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	hpas, err := client.AutoscalingV2beta2().HorizontalPodAutoscalers(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	targets := make(map[hpaTargetKey]*corev1.PodTemplateSpec)
+
+	deployments, err := client.AppsV1().Deployments(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i, deployment := range deployments.Items {
+		key := hpaTargetKey{"Deployment", types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name}}
+		targets[key] = &deployments.Items[i].Spec.Template
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i, sts := range statefulSets.Items {
+		key := hpaTargetKey{"StatefulSet", types.NamespacedName{Namespace: sts.Namespace, Name: sts.Name}}
+		targets[key] = &statefulSets.Items[i].Spec.Template
+	}
+
+	now := time.Now()
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, hpa := range hpas.Items {
+		problems := getHPAProblems(hpa, targets, o.maxedFor, now)
+		if len(problems) == 0 {
+			continue
+		}
+
+		minReplicas := "<unset>"
+		if hpa.Spec.MinReplicas != nil {
+			minReplicas = strconv.Itoa(int(*hpa.Spec.MinReplicas))
+		}
+
+		age := getAge(hpa.CreationTimestamp)
+		reference := hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name
+		row := []string{hpa.Name, reference, minReplicas, strconv.Itoa(int(hpa.Spec.MaxReplicas)), strconv.Itoa(int(hpa.Status.CurrentReplicas)), strings.Join(problems, ","), age}
+		if o.allNamespaces {
+			row = append([]string{hpa.Namespace}, row...)
+		}
+		uids = append(uids, hpa.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "REFERENCE", "MINPODS", "MAXPODS", "REPLICAS", "PROBLEMS", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace, "MINPODS", "MAXPODS", "REPLICAS"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getHPAProblems returns the reasons a HorizontalPodAutoscaler is considered
+// unhealthy. The targets hold the Pod templates of the Deployments and
+// StatefulSets; other kinds of targets are not checked.
+func getHPAProblems(hpa autoscalingv2beta2.HorizontalPodAutoscaler, targets map[hpaTargetKey]*corev1.PodTemplateSpec, maxedFor time.Duration, now time.Time) []string {
+	var problems []string
+
+	for _, condition := range hpa.Status.Conditions {
+		if condition.Type == autoscalingv2beta2.ScalingActive && condition.Status == corev1.ConditionFalse && condition.Reason != "ScalingDisabled" {
+			problems = append(problems, fmt.Sprintf("MetricsUnavailable(%s)", condition.Reason))
+		}
+	}
+
+	if hpa.Spec.MaxReplicas > 0 && hpa.Status.CurrentReplicas >= hpa.Spec.MaxReplicas {
+		maxed := now.Sub(getHPAMaxedSince(hpa).Time)
+		if maxed > maxedFor {
+			problems = append(problems, fmt.Sprintf("AtMaxReplicas(for %s)", duration.HumanDuration(maxed)))
+		}
+	}
+
+	ref := hpa.Spec.ScaleTargetRef
+	if ref.Kind != "Deployment" && ref.Kind != "StatefulSet" {
+		return problems
+	}
+
+	template, ok := targets[hpaTargetKey{ref.Kind, types.NamespacedName{Namespace: hpa.Namespace, Name: ref.Name}}]
+	if !ok {
+		return append(problems, fmt.Sprintf("TargetNotFound(%s/%s)", strings.ToLower(ref.Kind), ref.Name))
+	}
+
+	for _, resource := range getHPAUtilizationResources(hpa) {
+		var containers []string
+		for _, container := range template.Spec.Containers {
+			if _, ok := container.Resources.Requests[resource]; !ok {
+				containers = append(containers, container.Name)
+			}
+		}
+		if len(containers) > 0 {
+			problems = append(problems, fmt.Sprintf("MissingRequests(%s: %s)", resource, strings.Join(containers, ",")))
+		}
+	}
+
+	return problems
+}
+
+// getHPAUtilizationResources returns the resources the HorizontalPodAutoscaler
+// scales on as a percentage of the requests of the containers.
+func getHPAUtilizationResources(hpa autoscalingv2beta2.HorizontalPodAutoscaler) []corev1.ResourceName {
+	var resources []corev1.ResourceName
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type == autoscalingv2beta2.ResourceMetricSourceType && metric.Resource != nil && metric.Resource.Target.Type == autoscalingv2beta2.UtilizationMetricType {
+			resources = append(resources, metric.Resource.Name)
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i] < resources[j] })
+	return resources
+}
+
+// getHPAMaxedSince approximates since when the HorizontalPodAutoscaler runs at
+// maxReplicas, using its ScalingLimited condition or its last scale time.
+func getHPAMaxedSince(hpa autoscalingv2beta2.HorizontalPodAutoscaler) metav1.Time {
+	for _, condition := range hpa.Status.Conditions {
+		if condition.Type == autoscalingv2beta2.ScalingLimited && condition.Status == corev1.ConditionTrue && condition.Reason == "TooManyReplicas" {
+			return condition.LastTransitionTime
+		}
+	}
+
+	if hpa.Status.LastScaleTime != nil {
+		return *hpa.Status.LastScaleTime
+	}
+	return hpa.CreationTimestamp
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetHPAProblems(t *testing.T) {
+	now := time.Now()
+	withRequests := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Name:      "app",
+		Resources: corev1.ResourceRequirements{Requests: newTestResources("100m", "")},
+	}}}}
+	withoutRequests := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "app"},
+		{Name: "sidecar"},
+	}}}
+
+	targets := map[hpaTargetKey]*corev1.PodTemplateSpec{
+		{"Deployment", types.NamespacedName{Namespace: "default", Name: "web"}}: withRequests,
+		{"StatefulSet", types.NamespacedName{Namespace: "default", Name: "db"}}: withoutRequests,
+	}
+
+	metricsUnavailable := newTestHPA("Deployment", "web")
+	metricsUnavailable.Status.Conditions = []autoscalingv2beta2.HorizontalPodAutoscalerCondition{
+		{Type: autoscalingv2beta2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetResourceMetric"},
+	}
+
+	maxedLongAgo := newTestHPA("Deployment", "web")
+	maxedLongAgo.Status.CurrentReplicas = 5
+	maxedLongAgo.Status.Conditions = []autoscalingv2beta2.HorizontalPodAutoscalerCondition{
+		{Type: autoscalingv2beta2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas", LastTransitionTime: metav1.Time{Time: now.Add(-2 * time.Hour)}},
+	}
+
+	maxedRecently := newTestHPA("Deployment", "web")
+	maxedRecently.Status.CurrentReplicas = 5
+	maxedRecently.Status.LastScaleTime = &metav1.Time{Time: now.Add(-5 * time.Minute)}
+
+	tests := []struct {
+		name string
+		hpa  autoscalingv2beta2.HorizontalPodAutoscaler
+		want []string
+	}{
+		{
+			name: "HorizontalPodAutoscaler with metrics and room to scale is expected to be healthy",
+			hpa:  newTestHPA("Deployment", "web"),
+			want: nil,
+		},
+		{
+			name: "HorizontalPodAutoscaler unable to fetch metrics is expected to be reported",
+			hpa:  metricsUnavailable,
+			want: []string{"MetricsUnavailable(FailedGetResourceMetric)"},
+		},
+		{
+			name: "HorizontalPodAutoscaler pinned at maxReplicas for too long is expected to be reported",
+			hpa:  maxedLongAgo,
+			want: []string{"AtMaxReplicas(for 120m)"},
+		},
+		{
+			name: "HorizontalPodAutoscaler that recently reached maxReplicas is expected to be healthy",
+			hpa:  maxedRecently,
+			want: nil,
+		},
+		{
+			name: "HorizontalPodAutoscaler targeting a missing Deployment is expected to be reported",
+			hpa:  newTestHPA("Deployment", "api"),
+			want: []string{"TargetNotFound(deployment/api)"},
+		},
+		{
+			name: "HorizontalPodAutoscaler targeting containers without CPU requests is expected to be reported",
+			hpa:  newTestHPA("StatefulSet", "db"),
+			want: []string{"MissingRequests(cpu: app,sidecar)"},
+		},
+		{
+			name: "HorizontalPodAutoscaler targeting another kind is expected not to be checked",
+			hpa:  newTestHPA("ReplicaSet", "legacy"),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getHPAProblems(tt.hpa, targets, time.Hour, now))
+		})
+	}
+}
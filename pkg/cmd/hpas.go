@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newHPAsCommand provides the base command when called without any subcommands.
+func newHPAsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "hpas",
+		Short:        "Find HorizontalPodAutoscalers in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newUnhealthyHPAsCommand(factory, options))
+
+	return cmd
+}
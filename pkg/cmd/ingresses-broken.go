@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// annotationIngressClass is the legacy annotation used to select an ingress controller.
+	annotationIngressClass = "kubernetes.io/ingress.class"
+	// annotationIsDefaultIngressClass marks an IngressClass as the default of the cluster.
+	annotationIsDefaultIngressClass = "ingressclass.kubernetes.io/is-default-class"
+)
+
+// BrokenIngressesOptions embeds JanitorOptions struct.
+type BrokenIngressesOptions struct {
+	JanitorOptions
+}
+
+// ingressFinding describes a problem affecting a host and path of an Ingress.
+type ingressFinding struct {
+	host    string
+	path    string
+	problem string
+}
+
+// ingressContext holds the objects an Ingress is checked against.
+type ingressContext struct {
+	services        map[types.NamespacedName]corev1.Service
+	readyEndpoints  map[types.NamespacedName]int
+	secrets         map[types.NamespacedName]bool
+	classes         map[string]bool
+	hasDefaultClass bool
+	checkClasses    bool
+}
+
+// newBrokenIngressesOptions creates an instance of BrokenIngressesOptions.
+func newBrokenIngressesOptions(options JanitorOptions) *BrokenIngressesOptions {
+	return &BrokenIngressesOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newBrokenIngressesCommand returns a cobra command wrapping BrokenIngressesOptions.
+func newBrokenIngressesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newBrokenIngressesOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "broken",
+		Short:        "List Ingresses pointing to missing Services, ports, Secrets or IngressClasses",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run lists misconfigured Ingresses.
+func (o *BrokenIngressesOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	ingresses, err := listIngresses(ctx, client, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	ic := ingressContext{
+		services: make(map[types.NamespacedName]corev1.Service),
+		secrets:  make(map[types.NamespacedName]bool),
+		classes:  make(map[string]bool),
+	}
+
+	services, err := client.CoreV1().Services(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, svc := range services.Items {
+		ic.services[types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}] = svc
+	}
+
+	secrets, err := client.CoreV1().Secrets(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		ic.secrets[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}] = true
+	}
+
+	ic.readyEndpoints, err = getReadyEndpoints(ctx, client, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	classes, err := listIngressClasses(ctx, client)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		ic.checkClasses = true
+		for _, class := range classes {
+			ic.classes[class.Name] = true
+			if class.Annotations[annotationIsDefaultIngressClass] == "true" {
+				ic.hasDefaultClass = true
+			}
+		}
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, ing := range ingresses {
+		age := getAge(ing.CreationTimestamp)
+		for _, finding := range getIngressFindings(ing, ic) {
+			row := []string{ing.Name, finding.host, finding.path, finding.problem, age}
+			if o.allNamespaces {
+				row = append([]string{ing.Namespace}, row...)
+			}
+			uids = append(uids, ing.UID)
+			matrix = append(matrix, row)
+		}
+	}
+
+	headers := []string{"NAME", "HOST", "PATH", "PROBLEM", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getIngressFindings returns the problems found in the rules, TLS sections
+// and class of the Ingress.
+func getIngressFindings(ing networkingv1.Ingress, ic ingressContext) []ingressFinding {
+	var findings []ingressFinding
+
+	if ic.checkClasses {
+		if name := ing.Spec.IngressClassName; name != nil {
+			if !ic.classes[*name] {
+				findings = append(findings, ingressFinding{"*", "*", fmt.Sprintf("IngressClassNotFound(%s)", *name)})
+			}
+		} else if _, ok := ing.Annotations[annotationIngressClass]; !ok && !ic.hasDefaultClass {
+			findings = append(findings, ingressFinding{"*", "*", "NoIngressClass"})
+		}
+	}
+
+	if ing.Spec.DefaultBackend != nil {
+		if problem := getIngressBackendProblem(ing.Namespace, *ing.Spec.DefaultBackend, ic); problem != "" {
+			findings = append(findings, ingressFinding{"*", "*", problem})
+		}
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			p := path.Path
+			if p == "" {
+				p = "/"
+			}
+			if problem := getIngressBackendProblem(ing.Namespace, path.Backend, ic); problem != "" {
+				findings = append(findings, ingressFinding{host, p, problem})
+			}
+		}
+	}
+
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+
+		if !ic.secrets[types.NamespacedName{Namespace: ing.Namespace, Name: tls.SecretName}] {
+			hosts := strings.Join(tls.Hosts, ",")
+			if hosts == "" {
+				hosts = "*"
+			}
+			findings = append(findings, ingressFinding{hosts, "*", fmt.Sprintf("TLSSecretNotFound(%s)", tls.SecretName)})
+		}
+	}
+
+	return findings
+}
+
+// getIngressBackendProblem returns the problem of an Ingress backend,
+// or an empty string when the backend Service can serve traffic.
+func getIngressBackendProblem(namespace string, backend networkingv1.IngressBackend, ic ingressContext) string {
+	if backend.Service == nil {
+		return ""
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: backend.Service.Name}
+	svc, ok := ic.services[key]
+	if !ok {
+		return fmt.Sprintf("ServiceNotFound(%s)", backend.Service.Name)
+	}
+
+	// ExternalName Services are resolved through DNS by the ingress
+	// controller, their ports and endpoints cannot be checked.
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return ""
+	}
+
+	port := backend.Service.Port
+	found := false
+	for _, sp := range svc.Spec.Ports {
+		if (port.Name != "" && sp.Name == port.Name) || (port.Name == "" && sp.Port == port.Number) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		portName := port.Name
+		if portName == "" {
+			portName = fmt.Sprint(port.Number)
+		}
+		return fmt.Sprintf("ServicePortNotFound(%s:%s)", backend.Service.Name, portName)
+	}
+
+	if ic.readyEndpoints[key] == 0 {
+		return fmt.Sprintf("NoReadyEndpoints(%s)", backend.Service.Name)
+	}
+
+	return ""
+}
+
+// listIngresses lists the Ingresses of the namespace. Clusters older than 1.19
+// only serve them as networking.k8s.io/v1beta1, which are converted to v1.
+func listIngresses(ctx context.Context, client kubernetes.Interface, namespace string) ([]networkingv1.Ingress, error) {
+	list, err := client.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		return list.Items, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	legacy, err := client.NetworkingV1beta1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ingresses := make([]networkingv1.Ingress, 0, len(legacy.Items))
+	for _, ing := range legacy.Items {
+		ingresses = append(ingresses, convertIngressV1beta1(ing))
+	}
+	return ingresses, nil
+}
+
+// listIngressClasses lists the IngressClasses of the cluster, falling back to
+// networking.k8s.io/v1beta1 on clusters older than 1.19. The error is NotFound
+// on clusters older than 1.18, which have no IngressClasses.
+func listIngressClasses(ctx context.Context, client kubernetes.Interface) ([]metav1.ObjectMeta, error) {
+	list, err := client.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err == nil {
+		classes := make([]metav1.ObjectMeta, 0, len(list.Items))
+		for _, class := range list.Items {
+			classes = append(classes, class.ObjectMeta)
+		}
+		return classes, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	legacy, err := client.NetworkingV1beta1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	classes := make([]metav1.ObjectMeta, 0, len(legacy.Items))
+	for _, class := range legacy.Items {
+		classes = append(classes, class.ObjectMeta)
+	}
+	return classes, nil
+}
+
+// convertIngressV1beta1 converts the fields of a networking.k8s.io/v1beta1
+// Ingress checked by getIngressFindings to networking.k8s.io/v1.
+func convertIngressV1beta1(ing networkingv1beta1.Ingress) networkingv1.Ingress {
+	converted := networkingv1.Ingress{
+		ObjectMeta: ing.ObjectMeta,
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ing.Spec.IngressClassName,
+		},
+	}
+
+	if ing.Spec.Backend != nil {
+		backend := convertIngressBackendV1beta1(*ing.Spec.Backend)
+		converted.Spec.DefaultBackend = &backend
+	}
+
+	for _, tls := range ing.Spec.TLS {
+		converted.Spec.TLS = append(converted.Spec.TLS, networkingv1.IngressTLS{Hosts: tls.Hosts, SecretName: tls.SecretName})
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		r := networkingv1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			r.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				r.HTTP.Paths = append(r.HTTP.Paths, networkingv1.HTTPIngressPath{
+					Path:    path.Path,
+					Backend: convertIngressBackendV1beta1(path.Backend),
+				})
+			}
+		}
+		converted.Spec.Rules = append(converted.Spec.Rules, r)
+	}
+
+	return converted
+}
+
+// convertIngressBackendV1beta1 converts a networking.k8s.io/v1beta1 backend,
+// whose servicePort is either a port name or number, to networking.k8s.io/v1.
+func convertIngressBackendV1beta1(backend networkingv1beta1.IngressBackend) networkingv1.IngressBackend {
+	if backend.ServiceName == "" {
+		return networkingv1.IngressBackend{Resource: backend.Resource}
+	}
+
+	port := networkingv1.ServiceBackendPort{Number: backend.ServicePort.IntVal}
+	if backend.ServicePort.Type == intstr.String {
+		port = networkingv1.ServiceBackendPort{Name: backend.ServicePort.StrVal}
+	}
+	return networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{Name: backend.ServiceName, Port: port},
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetIngressFindings(t *testing.T) {
+	className := "nginx"
+	missingClass := "traefik"
+
+	web := types.NamespacedName{Namespace: "default", Name: "web"}
+	idle := types.NamespacedName{Namespace: "default", Name: "idle"}
+	external := types.NamespacedName{Namespace: "default", Name: "external"}
+
+	ic := ingressContext{
+		services: map[types.NamespacedName]corev1.Service{
+			web:      {Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}}},
+			idle:     {Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}}},
+			external: {Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "api.example.org"}},
+		},
+		readyEndpoints: map[types.NamespacedName]int{web: 2},
+		secrets:        map[types.NamespacedName]bool{{Namespace: "default", Name: "web-tls"}: true},
+		classes:        map[string]bool{className: true},
+		checkClasses:   true,
+	}
+
+	tests := []struct {
+		name string
+		ing  networkingv1.Ingress
+		want []ingressFinding
+	}{
+		{
+			name: "Ingress pointing to a ready Service is expected to be healthy",
+			ing: newTestIngress(&className, networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+				networkingv1.IngressTLS{Hosts: []string{"example.com"}, SecretName: "web-tls"}),
+			want: nil,
+		},
+		{
+			name: "Ingress pointing to a missing Service is expected to be reported",
+			ing:  newTestIngress(&className, networkingv1.IngressServiceBackend{Name: "api", Port: networkingv1.ServiceBackendPort{Number: 80}}),
+			want: []ingressFinding{{"example.com", "/api", "ServiceNotFound(api)"}},
+		},
+		{
+			name: "Ingress pointing to a missing Service port is expected to be reported",
+			ing:  newTestIngress(&className, networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 8080}}),
+			want: []ingressFinding{{"example.com", "/api", "ServicePortNotFound(web:8080)"}},
+		},
+		{
+			name: "Ingress pointing to a Service without endpoints is expected to be reported",
+			ing:  newTestIngress(&className, networkingv1.IngressServiceBackend{Name: "idle", Port: networkingv1.ServiceBackendPort{Number: 80}}),
+			want: []ingressFinding{{"example.com", "/api", "NoReadyEndpoints(idle)"}},
+		},
+		{
+			name: "Ingress pointing to an ExternalName Service is expected not to be checked",
+			ing:  newTestIngress(&className, networkingv1.IngressServiceBackend{Name: "external", Port: networkingv1.ServiceBackendPort{Number: 443}}),
+			want: nil,
+		},
+		{
+			name: "Ingress with a missing TLS Secret and IngressClass is expected to be reported",
+			ing: newTestIngress(&missingClass, networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}},
+				networkingv1.IngressTLS{Hosts: []string{"example.com"}, SecretName: "missing-tls"}),
+			want: []ingressFinding{
+				{"*", "*", "IngressClassNotFound(traefik)"},
+				{"example.com", "*", "TLSSecretNotFound(missing-tls)"},
+			},
+		},
+		{
+			name: "Ingress without a class and no default IngressClass is expected to be reported",
+			ing:  newTestIngress(nil, networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}),
+			want: []ingressFinding{{"*", "*", "NoIngressClass"}},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getIngressFindings(tc.ing, ic)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBrokenIngressesRunV1beta1(t *testing.T) {
+	ing := networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "legacy"},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+						Paths: []networkingv1beta1.HTTPIngressPath{{
+							Path:    "/",
+							Backend: networkingv1beta1.IngressBackend{ServiceName: "web", ServicePort: intstr.FromString("metrics")},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	svc := newTestService(testNamespace, "web", intstr.FromInt(8080))
+	client := fake.NewSimpleClientset(&ing, &svc)
+
+	// Clusters older than 1.19 do not serve networking.k8s.io/v1.
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().GroupVersion() == networkingv1.SchemeGroupVersion {
+			return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), "")
+		}
+		return false, nil, nil
+	})
+
+	options, _, out, errOut := newFakeJanitorOptions(client)
+	o := newBrokenIngressesOptions(options)
+
+	assert.NoError(t, o.Run(context.Background(), true))
+	assert.Empty(t, errOut.String())
+	assert.Contains(t, out.String(), "ServicePortNotFound(web:metrics)")
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newIngressesCommand provides the base command when called without any subcommands.
+func newIngressesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "ingresses",
+		Short:        "Find Ingresses in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newBrokenIngressesCommand(factory, options))
+
+	return cmd
+}
@@ -1,30 +1,119 @@
 package cmd
 
 import (
+	"strconv"
+
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
-var cmdExample = `# List Pods that are in a pending state (waiting to be scheduled)
+var cmdExample = `# List Namespaces stuck in Terminating and the resources left in them.
+kubectl janitor namespaces terminating
+
+# List Nodes that are not ready, under pressure, cordoned for too long or not heartbeating.
+kubectl janitor nodes unhealthy
+
+# Predict what would block kubectl drain on a Node.
+kubectl janitor nodes drain-check node-1
+
+# Aggregate the Warning Events of the last hour by reason across all Namespaces.
+kubectl janitor events warnings -A --since 1h
+
+# Diagnose a Pod and rank the likely root causes of its problems.
+kubectl janitor explain pod/web-0
+
+# List objects of any type whose deletion is held by finalizers.
+kubectl janitor finalizers stuck -A
+
+# Remove a finalizer from the objects it holds, after a backup and confirmation.
+kubectl janitor finalizers stuck -A --remove-finalizer example.com/cleanup --backup-dir ./backups
+
+# List HorizontalPodAutoscalers that cannot scale, are pinned at maxReplicas or target missing workloads.
+kubectl janitor hpas unhealthy
+
+# List objects whose owners no longer exist and Pods without any owner.
+kubectl janitor orphans -A
+
+# List PodDisruptionBudgets that block evictions or are misconfigured.
+kubectl janitor pdbs blocking
+
+# List Pods that are in a pending state (waiting to be scheduled)
 kubectl janitor pods unscheduled
 
+# Count the unscheduled Pods of all Namespaces by scheduling failure reason.
+kubectl janitor pods unscheduled -A --summarize
+
 # List Pods in an unhealthy state.
 kubectl janitor pods unhealthy
 
+# Aggregate unhealthy Pods by node to spot a bad node.
+kubectl janitor pods unhealthy --group-by node
+
+# Explain the reasons of the unhealthy Pods and suggest the next commands to run.
+kubectl janitor pods unhealthy --hints
+
+# Show the last 50 log lines of the previous instance of crashing containers.
+kubectl janitor pods unhealthy --logs=50
+
 # List Pods that are currently in a running phase but not ready for some reason.
 kubectl janitor pods unready
 
+# List Pods referencing PersistentVolumeClaims, ConfigMaps, Secrets or keys that do not exist.
+kubectl janitor pods broken-refs
+
 # List the current statuses of the Pods and their respective count.
 kubectl janitor pods status
 
-# List Jobs that have failed to run and have restartPolicy: Never.
+# List Ingresses pointing to missing Services, ports, Secrets or IngressClasses.
+kubectl janitor ingresses broken
+
+# List Jobs that have failed to run.
 kubectl janitor jobs failed
 
+# List failed Jobs whose Pods have restartPolicy: OnFailure.
+kubectl janitor jobs failed --restart-policy OnFailure
+
+# List succeeded Jobs older than a day that are never cleaned up, and delete them.
+kubectl janitor jobs completed --older-than 24h --delete
+
+# List Jobs that are still active after an hour or close to their activeDeadlineSeconds.
+kubectl janitor jobs stuck
+
+# List CronJobs that are suspended, missed their schedule or keep failing.
+kubectl janitor cronjobs unhealthy
+
+# List Services that have no ready endpoints, match no Pods or point to an unknown targetPort.
+kubectl janitor services no-endpoints
+
+# List LoadBalancer Services waiting for an external IP for more than 10 minutes.
+kubectl janitor services pending-lb --older-than 10m
+
 # List PesistentVolumes that are available for claim.
 kubectl janitor pvs unclaimed
 
-# List PersistentVolumeClaims in an pending state (unbound).
+# List PersistentVolumes released by their claim and the capacity they waste.
+kubectl janitor pvs released
+
+# Make all Released PersistentVolumes Available again, after a backup and confirmation.
+kubectl janitor pvs reclaim --backup-dir ./backups
+
+# Preview the deletion of a Released PersistentVolume.
+kubectl janitor pvs reclaim pvc-0a1b2c3d --delete --dry-run
+
+# List PersistentVolumes whose automatic reclamation failed.
+kubectl janitor pvs failed
+
+# List PersistentVolumeClaims in an pending state (unbound) and why they are stuck.
 kubectl janitor pvcs pending
+
+# List Bound PersistentVolumeClaims that are not mounted by any Pod.
+kubectl janitor pvcs unused
+
+# Attach the latest 3 Events of every unscheduled Pod to the output.
+kubectl janitor pods unscheduled -o wide --events=3
+
+# Print the unhealthy Pods of all Namespaces as JSON.
+kubectl janitor pods unhealthy -A -o json
 `
 
 // NewJanitorCommand provides the base command when called without any subcommands.
@@ -39,6 +128,10 @@ func NewJanitorCommand() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().Bool("no-headers", false, "Don't print headers (default print headers).")
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format. One of: wide|json|yaml.")
+	cmd.PersistentFlags().Int("events", 0, "Attach the latest N Events of every reported object to the wide, json and yaml output (use --events=N). Implies -o wide when --output is not set.")
+	cmd.PersistentFlags().Lookup("events").NoOptDefVal = strconv.Itoa(defaultEvents)
+	cmd.PersistentFlags().String("janitor-config", "", "Path to the janitor config file (default ~/.kube/janitor.yaml).")
 
 	flags := cmd.PersistentFlags()
 	o.ConfigFlags.AddFlags(flags)
@@ -48,10 +141,21 @@ func NewJanitorCommand() *cobra.Command {
 
 	f := cmdutil.NewFactory(matchVersionFlags)
 
+	cmd.AddCommand(newCronJobsCommand(f, o))
+	cmd.AddCommand(newEventsCommand(f, o))
+	cmd.AddCommand(newExplainCommand(f, o))
+	cmd.AddCommand(newFinalizersCommand(f, o))
+	cmd.AddCommand(newHPAsCommand(f, o))
+	cmd.AddCommand(newIngressesCommand(f, o))
 	cmd.AddCommand(newJobsCommand(f, o))
+	cmd.AddCommand(newNamespacesCommand(f, o))
+	cmd.AddCommand(newNodesCommand(f, o))
+	cmd.AddCommand(newOrphansCommand(f, o))
+	cmd.AddCommand(newPDBsCommand(f, o))
 	cmd.AddCommand(newPodsCommand(f, o))
 	cmd.AddCommand(newPVCsCommand(f, o))
 	cmd.AddCommand(newPVsCommand(f, o))
+	cmd.AddCommand(newServicesCommand(f, o))
 
 	return cmd
 }
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// CompletedJobsOptions embeds JanitorOptions struct.
+type CompletedJobsOptions struct {
+	JanitorOptions
+	olderThan time.Duration
+	delete    bool
+}
+
+// newCompletedJobsOptions creates an instance of CompletedJobsOptions.
+func newCompletedJobsOptions(options JanitorOptions) *CompletedJobsOptions {
+	return &CompletedJobsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newCompletedJobsCommand returns a cobra command wrapping CompletedJobsOptions.
+func newCompletedJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newCompletedJobsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "completed",
+		Short:        "List succeeded Jobs that are never cleaned up (no ttlSecondsAfterFinished and out of any CronJob history limit)",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.olderThan, "older-than", 24*time.Hour, "List Jobs that completed longer ago than this duration.")
+	cmd.Flags().BoolVar(&o.delete, "delete", false, "Delete the listed Jobs and their Pods.")
+
+	return cmd
+}
+
+// Run lists completed Jobs that linger in the cluster.
+func (o *CompletedJobsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	jobs, err := client.BatchV1().Jobs(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	cronJobs, err := client.BatchV1beta1().CronJobs(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	retained := getCronJobRetainedJobs(cronJobs.Items, jobs.Items)
+
+	now := time.Now()
+
+	var matrix [][]string
+	var uids []types.UID
+	var lingering []batchv1.Job
+
+	for _, job := range jobs.Items {
+		if !isJobLingering(job, retained, o.olderThan, now) {
+			continue
+		}
+		lingering = append(lingering, job)
+
+		completed := getAge(*job.Status.CompletionTime)
+		age := getAge(job.CreationTimestamp)
+		row := []string{job.Name, strconv.Itoa(int(job.Status.Succeeded)), completed, age}
+		if o.allNamespaces {
+			row = append([]string{job.Namespace}, row...)
+		}
+		uids = append(uids, job.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "SUCCEEDED", "COMPLETED", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace, "SUCCEEDED"); err != nil {
+		return err
+	}
+
+	if !o.delete {
+		return nil
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	for _, job := range lingering {
+		err := client.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil {
+			fmt.Fprintf(o.Streams.ErrOut, "failed to delete job.batch/%s in %s namespace: %v\n", job.Name, job.Namespace, err)
+			continue
+		}
+		fmt.Fprintf(o.Streams.ErrOut, "job.batch/%s deleted\n", job.Name)
+	}
+
+	return nil
+}
+
+// getCronJobRetainedJobs returns the UIDs of the succeeded Jobs their CronJob
+// keeps within its successfulJobsHistoryLimit, which it prunes once they fall
+// out of it. The newest Jobs are kept first, and a CronJob without a limit keeps
+// none of them, since it never prunes any.
+func getCronJobRetainedJobs(cronJobs []batchv1beta1.CronJob, jobs []batchv1.Job) map[types.UID]bool {
+	owned := make(map[types.UID][]batchv1.Job)
+	for _, job := range jobs {
+		if owner := metav1.GetControllerOf(&job); owner != nil && owner.Kind == "CronJob" && isJobComplete(job) && job.Status.CompletionTime != nil {
+			owned[owner.UID] = append(owned[owner.UID], job)
+		}
+	}
+
+	retained := make(map[types.UID]bool)
+	for _, cronJob := range cronJobs {
+		if cronJob.Spec.SuccessfulJobsHistoryLimit == nil {
+			continue
+		}
+
+		history := owned[cronJob.UID]
+		sort.Slice(history, func(i, j int) bool {
+			return history[i].Status.CompletionTime.After(history[j].Status.CompletionTime.Time)
+		})
+		for i, job := range history {
+			if i == int(*cronJob.Spec.SuccessfulJobsHistoryLimit) {
+				break
+			}
+			retained[job.UID] = true
+		}
+	}
+	return retained
+}
+
+// isJobLingering checks whether a succeeded Job will never be cleaned up automatically.
+// The retained Jobs are kept by the history limit of their CronJob.
+func isJobLingering(job batchv1.Job, retained map[types.UID]bool, olderThan time.Duration, now time.Time) bool {
+	if !isJobComplete(job) || job.Status.CompletionTime == nil {
+		return false
+	}
+
+	if job.Spec.TTLSecondsAfterFinished != nil {
+		return false
+	}
+
+	if retained[job.UID] {
+		return false
+	}
+
+	return now.Sub(job.Status.CompletionTime.Time) > olderThan
+}
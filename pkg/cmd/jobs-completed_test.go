@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsJobLingering(t *testing.T) {
+	now := time.Now()
+	ttl := int32(3600)
+
+	withTTL := newTestCompletedJob(testNamespace, "backup", now.Add(-48*time.Hour))
+	withTTL.Spec.TTLSecondsAfterFinished = &ttl
+
+	retainedJob := newTestCompletedJob(testNamespace, "backup-1", now.Add(-48*time.Hour))
+	retainedJob.UID = "backup-1"
+
+	prunableJob := newTestCompletedJob(testNamespace, "backup-0", now.Add(-72*time.Hour))
+	prunableJob.UID = "backup-0"
+
+	retained := map[types.UID]bool{retainedJob.UID: true}
+
+	tests := []struct {
+		name string
+		job  batchv1.Job
+		want bool
+	}{
+		{
+			name: "Job that completed long ago without a TTL is expected to linger",
+			job:  newTestCompletedJob(testNamespace, "backup", now.Add(-48*time.Hour)),
+			want: true,
+		},
+		{
+			name: "Job that completed recently is not expected to linger",
+			job:  newTestCompletedJob(testNamespace, "backup", now.Add(-time.Hour)),
+			want: false,
+		},
+		{
+			name: "Job with ttlSecondsAfterFinished is not expected to linger",
+			job:  withTTL,
+			want: false,
+		},
+		{
+			name: "Job retained by the history limit of its CronJob is not expected to linger",
+			job:  retainedJob,
+			want: false,
+		},
+		{
+			name: "Job out of the history limit of its CronJob is expected to linger",
+			job:  prunableJob,
+			want: true,
+		},
+		{
+			name: "Job that is still running is not expected to linger",
+			job:  batchv1.Job{Status: batchv1.JobStatus{Active: 1}},
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := isJobLingering(tc.job, retained, 24*time.Hour, now)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetCronJobRetainedJobs(t *testing.T) {
+	now := time.Now()
+	limit := int32(2)
+
+	newCronJob := func(name string, limit *int32) batchv1beta1.CronJob {
+		return batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name, UID: types.UID(name)},
+			Spec:       batchv1beta1.CronJobSpec{SuccessfulJobsHistoryLimit: limit},
+		}
+	}
+	newJob := func(cronJob string, i int) batchv1.Job {
+		name := fmt.Sprintf("%s-%d", cronJob, i)
+		job := newTestCompletedJob(testNamespace, name, now.Add(-time.Duration(i)*time.Hour))
+		job.UID = types.UID(name)
+		job.OwnerReferences = []metav1.OwnerReference{newTestControllerRef("CronJob", cronJob, types.UID(cronJob))}
+		return job
+	}
+
+	running := newJob("limited", 0)
+	running.Status = batchv1.JobStatus{Active: 1}
+
+	tests := []struct {
+		name     string
+		cronJobs []batchv1beta1.CronJob
+		jobs     []batchv1.Job
+		want     map[types.UID]bool
+	}{
+		{
+			name:     "newest Jobs within the history limit are expected to be retained",
+			cronJobs: []batchv1beta1.CronJob{newCronJob("limited", &limit)},
+			jobs:     []batchv1.Job{newJob("limited", 3), newJob("limited", 1), newJob("limited", 2)},
+			want:     map[types.UID]bool{"limited-1": true, "limited-2": true},
+		},
+		{
+			name:     "running Jobs are not expected to count against the history limit",
+			cronJobs: []batchv1beta1.CronJob{newCronJob("limited", &limit)},
+			jobs:     []batchv1.Job{running, newJob("limited", 1), newJob("limited", 2), newJob("limited", 3)},
+			want:     map[types.UID]bool{"limited-1": true, "limited-2": true},
+		},
+		{
+			name:     "Jobs of a CronJob without a history limit are not expected to be retained",
+			cronJobs: []batchv1beta1.CronJob{newCronJob("unlimited", nil)},
+			jobs:     []batchv1.Job{newJob("unlimited", 1)},
+			want:     map[types.UID]bool{},
+		},
+		{
+			name: "Jobs whose CronJob no longer exists are not expected to be retained",
+			jobs: []batchv1.Job{newJob("deleted", 1)},
+			want: map[types.UID]bool{},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getCronJobRetainedJobs(tc.cronJobs, tc.jobs)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCompletedJobsRunDelete(t *testing.T) {
+	now := time.Now()
+	lingering := newTestCompletedJob(testNamespace, "report", now.Add(-48*time.Hour))
+	recent := newTestCompletedJob(testNamespace, "backup", now.Add(-time.Hour))
+	client := fake.NewSimpleClientset(&lingering, &recent)
+
+	options, _, out, errOut := newFakeJanitorOptions(client)
+	o := newCompletedJobsOptions(options)
+	o.olderThan = 24 * time.Hour
+	o.delete = true
+
+	assert.NoError(t, o.Run(context.Background(), true))
+	assert.Contains(t, out.String(), "report")
+	assert.NotContains(t, out.String(), "backup")
+	assert.Equal(t, "job.batch/report deleted\n", errOut.String())
+
+	jobs, err := client.BatchV1().Jobs(testNamespace).List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, jobs.Items, 1)
+	assert.Equal(t, "backup", jobs.Items[0].Name)
+}
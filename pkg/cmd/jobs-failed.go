@@ -1,19 +1,21 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
 // FailedJobsOptions embeds JanitorOptions struct.
 type FailedJobsOptions struct {
 	JanitorOptions
+	restartPolicy string
 }
 
 // newFailedJobsOptions create a instance of FailedJobsOptions.
@@ -23,13 +25,13 @@ func newFailedJobsOptions(options JanitorOptions) *FailedJobsOptions {
 	}
 }
 
-// newFailedJobsCommand returns a cobra command wrapping BlockedJobsOptions.
+// newFailedJobsCommand returns a cobra command wrapping FailedJobsOptions.
 func newFailedJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
 	o := newFailedJobsOptions(options)
 
 	cmd := &cobra.Command{
 		Use:          "failed",
-		Short:        "List Jobs that have failed to run and have restartPolicy: Never",
+		Short:        "List Jobs that have failed to run",
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.Complete(factory, c); err != nil {
@@ -47,11 +49,23 @@ func newFailedJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobr
 	}
 
 	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.restartPolicy, "restart-policy", "", "Only list Jobs whose Pod template uses this restartPolicy (Never or OnFailure).")
 
 	return cmd
 }
 
-// Run lists stuck Jobs that cannot restart.
+// Complete validates the --restart-policy flag before completing the JanitorOptions.
+func (o *FailedJobsOptions) Complete(factory cmdutil.Factory, cmd *cobra.Command) error {
+	switch corev1.RestartPolicy(o.restartPolicy) {
+	case "", corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure:
+	default:
+		return fmt.Errorf("invalid --restart-policy %q: must be Never or OnFailure", o.restartPolicy)
+	}
+
+	return o.JanitorOptions.Complete(factory, cmd)
+}
+
+// Run lists Jobs that have failed.
 func (o *FailedJobsOptions) Run(ctx context.Context, noHeader bool) error {
 	client, err := o.GetClient()
 	if err != nil {
@@ -64,27 +78,34 @@ func (o *FailedJobsOptions) Run(ctx context.Context, noHeader bool) error {
 	}
 
 	var matrix [][]string
+	var uids []types.UID
 
 	for _, job := range jobs.Items {
-		if job.Spec.Template.Spec.RestartPolicy == "Never" {
-			for _, c := range job.Status.Conditions {
-				if c.Reason == "BackoffLimitExceeded" || c.Reason == "DeadlineExceeded" {
-					age := getAge(job.CreationTimestamp)
-					row := []string{job.Name, c.Reason, c.Message, age}
-					if o.allNamespaces {
-						row = append([]string{job.Namespace}, row...)
-					}
-					matrix = append(matrix, row)
-				}
+		restartPolicy := job.Spec.Template.Spec.RestartPolicy
+		if o.restartPolicy != "" && string(restartPolicy) != o.restartPolicy {
+			continue
+		}
+
+		if c := getJobFailedCondition(job); c != nil {
+			age := getAge(job.CreationTimestamp)
+			row := []string{job.Name, string(restartPolicy), c.Reason, c.Message, age}
+			if o.allNamespaces {
+				row = append([]string{job.Namespace}, row...)
 			}
+			uids = append(uids, job.UID)
+			matrix = append(matrix, row)
 		}
 	}
 
-	headers := []string{"NAME", "REASON", "MESSAGE", "AGE"}
+	headers := []string{"NAME", "RESTART POLICY", "REASON", "MESSAGE", "AGE"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+func TestFailedJobsRun(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+
+	newJob := func(name string, restartPolicy corev1.RestartPolicy, failed bool) batchv1.Job {
+		job := newTestFailedJob(testNamespace, name, created)
+		if !failed {
+			job.Status = batchv1.JobStatus{Active: 1}
+		}
+		job.Spec.Template.Spec.RestartPolicy = restartPolicy
+		return job
+	}
+
+	jobs := []batchv1.Job{
+		newJob("never-failed", corev1.RestartPolicyNever, true),
+		newJob("never-active", corev1.RestartPolicyNever, false),
+		newJob("on-failure-failed", corev1.RestartPolicyOnFailure, true),
+		newJob("on-failure-active", corev1.RestartPolicyOnFailure, false),
+	}
+
+	tests := []struct {
+		name          string
+		restartPolicy string
+		want          []string
+	}{
+		{
+			name: "failed Jobs are expected to be listed whatever their restartPolicy",
+			want: []string{"never-failed", "on-failure-failed"},
+		},
+		{
+			name:          "only failed Jobs restarted on failure are expected to be listed",
+			restartPolicy: "OnFailure",
+			want:          []string{"on-failure-failed"},
+		},
+		{
+			name:          "only failed Jobs never restarted are expected to be listed",
+			restartPolicy: "Never",
+			want:          []string{"never-failed"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := fake.NewSimpleClientset(&jobs[0], &jobs[1], &jobs[2], &jobs[3])
+			options, _, out, _ := newFakeJanitorOptions(client)
+			o := newFailedJobsOptions(options)
+			o.restartPolicy = tt.restartPolicy
+
+			err := o.Run(context.Background(), true)
+			assert.NoError(t, err)
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				got = append(got, strings.Fields(line)[0])
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFailedJobsCommandInvalidRestartPolicy(t *testing.T) {
+	options, _, _, _ := newFakeJanitorOptions(fake.NewSimpleClientset())
+	cmd := newFailedJobsCommand(cmdutil.NewFactory(options.ConfigFlags), options)
+	cmd.SetArgs([]string{"--restart-policy", "Always"})
+	cmd.SetOut(options.Streams.Out)
+	cmd.SetErr(options.Streams.ErrOut)
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, `invalid --restart-policy "Always": must be Never or OnFailure`)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// defaultStuckJobAge is the default age after which an active Job is considered stuck.
+	defaultStuckJobAge = time.Hour
+	// defaultStuckJobDeadlineRatio is the default fraction of its activeDeadlineSeconds
+	// after which an active Job is considered stuck.
+	defaultStuckJobDeadlineRatio = 0.8
+)
+
+// StuckJobsOptions embeds JanitorOptions struct.
+type StuckJobsOptions struct {
+	JanitorOptions
+	olderThan     time.Duration
+	deadlineRatio float64
+}
+
+// newStuckJobsOptions creates an instance of StuckJobsOptions.
+func newStuckJobsOptions(options JanitorOptions) *StuckJobsOptions {
+	return &StuckJobsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newStuckJobsCommand returns a cobra command wrapping StuckJobsOptions.
+func newStuckJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newStuckJobsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "stuck",
+		Short:        "List Jobs that are still active for too long or close to their activeDeadlineSeconds",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.olderThan, "older-than", defaultStuckJobAge, "List active Jobs that started longer ago than this duration.")
+	cmd.Flags().Float64Var(&o.deadlineRatio, "deadline-ratio", defaultStuckJobDeadlineRatio, "List active Jobs that have used this fraction of their activeDeadlineSeconds.")
+
+	return cmd
+}
+
+// Run lists Jobs that are stuck in an active state.
+func (o *StuckJobsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	jobs, err := client.BatchV1().Jobs(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, job := range jobs.Items {
+		reason := getJobStuckReason(job, o.olderThan, o.deadlineRatio, now)
+		if reason == "" {
+			continue
+		}
+
+		deadline := "<none>"
+		if job.Spec.ActiveDeadlineSeconds != nil {
+			remaining := job.Status.StartTime.Add(time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second).Sub(now)
+			if remaining < 0 {
+				remaining = 0
+			}
+			deadline = duration.HumanDuration(remaining)
+		}
+
+		running := duration.HumanDuration(now.Sub(job.Status.StartTime.Time))
+		age := getAge(job.CreationTimestamp)
+		row := []string{job.Name, strconv.Itoa(int(job.Status.Active)), reason, running, deadline, age}
+		if o.allNamespaces {
+			row = append([]string{job.Namespace}, row...)
+		}
+		uids = append(uids, job.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "ACTIVE", "REASON", "RUNNING FOR", "DEADLINE IN", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace, "ACTIVE"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getJobStuckReason returns why an active Job is considered stuck,
+// or an empty string when it is not.
+func getJobStuckReason(job batchv1.Job, olderThan time.Duration, deadlineRatio float64, now time.Time) string {
+	if job.Status.Active == 0 || job.Status.StartTime == nil || job.Status.CompletionTime != nil || isJobFailed(job) {
+		return ""
+	}
+
+	running := now.Sub(job.Status.StartTime.Time)
+
+	if job.Spec.ActiveDeadlineSeconds != nil && deadlineRatio > 0 {
+		deadline := time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second
+		if float64(running) >= float64(deadline)*deadlineRatio {
+			return "NearActiveDeadline"
+		}
+	}
+
+	if olderThan > 0 && running > olderThan {
+		return "RunningTooLong"
+	}
+
+	return ""
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetJobStuckReason(t *testing.T) {
+	now := time.Now()
+	deadline := int64(600)
+
+	tests := []struct {
+		name string
+		job  batchv1.Job
+		want string
+	}{
+		{
+			name: "Job that started recently is not expected to be stuck",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Active:    1,
+					StartTime: &metav1.Time{Time: now.Add(-time.Minute)},
+				},
+			},
+			want: "",
+		},
+		{
+			name: "Job active for longer than the threshold is expected to be running too long",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Active:    1,
+					StartTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+				},
+			},
+			want: "RunningTooLong",
+		},
+		{
+			name: "Job close to its activeDeadlineSeconds is expected to be reported",
+			job: batchv1.Job{
+				Spec: batchv1.JobSpec{
+					ActiveDeadlineSeconds: &deadline,
+				},
+				Status: batchv1.JobStatus{
+					Active:    1,
+					StartTime: &metav1.Time{Time: now.Add(-9 * time.Minute)},
+				},
+			},
+			want: "NearActiveDeadline",
+		},
+		{
+			name: "Job that already failed is not expected to be stuck",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Active:     1,
+					StartTime:  &metav1.Time{Time: now.Add(-2 * time.Hour)},
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+				},
+			},
+			want: "",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getJobStuckReason(tc.job, time.Hour, 0.8, now)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -14,7 +14,9 @@ func newJobsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Comm
 		SilenceUsage: true,
 	}
 
+	cmd.AddCommand(newCompletedJobsCommand(factory, options))
 	cmd.AddCommand(newFailedJobsCommand(factory, options))
+	cmd.AddCommand(newStuckJobsCommand(factory, options))
 
 	return cmd
 }
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// namespaceDeletionConditions are the conditions set by the namespace controller
+// when it cannot finish deleting a Namespace.
+var namespaceDeletionConditions = map[corev1.NamespaceConditionType]bool{
+	corev1.NamespaceDeletionDiscoveryFailure: true,
+	corev1.NamespaceDeletionGVParsingFailure: true,
+	corev1.NamespaceDeletionContentFailure:   true,
+	corev1.NamespaceContentRemaining:         true,
+	corev1.NamespaceFinalizersRemaining:      true,
+}
+
+// TerminatingNamespacesOptions embeds JanitorOptions struct.
+type TerminatingNamespacesOptions struct {
+	JanitorOptions
+	olderThan time.Duration
+}
+
+// newTerminatingNamespacesOptions creates an instance of TerminatingNamespacesOptions.
+func newTerminatingNamespacesOptions(options JanitorOptions) *TerminatingNamespacesOptions {
+	return &TerminatingNamespacesOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newTerminatingNamespacesCommand returns a cobra command wrapping TerminatingNamespacesOptions.
+func newTerminatingNamespacesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newTerminatingNamespacesOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "terminating",
+		Short:        "List Namespaces stuck in Terminating and the resources left in them",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.olderThan, "older-than", 10*time.Minute, "List Namespaces that have been terminating for longer than this duration.")
+
+	return cmd
+}
+
+// Run lists the Namespaces stuck in Terminating and the resources holding them.
+func (o *TerminatingNamespacesOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	var terminating []corev1.Namespace
+	for _, ns := range namespaces.Items {
+		if ns.DeletionTimestamp != nil && now.Sub(ns.DeletionTimestamp.Time) > o.olderThan {
+			terminating = append(terminating, ns)
+		}
+	}
+
+	var resources []apiResource
+	var dynamicClient dynamic.Interface
+
+	if len(terminating) > 0 {
+		discoveryClient, err := o.GetDiscoveryClient()
+		if err != nil {
+			return err
+		}
+
+		resources, err = getServedResources(discoveryClient, true)
+		if err != nil {
+			return err
+		}
+
+		dynamicClient, err = o.GetDynamicClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	var summary, remaining [][]string
+	var uids []types.UID
+	seen := make(map[types.UID]bool)
+
+	for _, ns := range terminating {
+		finalizers := append([]string{}, ns.Finalizers...)
+		for _, finalizer := range ns.Spec.Finalizers {
+			finalizers = append(finalizers, string(finalizer))
+		}
+		if len(finalizers) == 0 {
+			finalizers = []string{"<none>"}
+		}
+
+		conditions := getNamespaceDeletionConditions(ns)
+		if len(conditions) == 0 {
+			conditions = []string{"<none>"}
+		}
+
+		terminatingFor := duration.HumanDuration(now.Sub(ns.DeletionTimestamp.Time))
+		uids = append(uids, ns.UID)
+		summary = append(summary, []string{ns.Name, terminatingFor, strings.Join(finalizers, ","), strings.Join(conditions, "; ")})
+
+		for _, resource := range resources {
+			list, err := dynamicClient.Resource(resource.GroupVersionResource).Namespace(ns.Name).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				// Resources that cannot be listed, for example because of RBAC
+				// or an unavailable aggregated API, may be what holds the
+				// Namespace, so they are reported rather than skipped.
+				remaining = append(remaining, []string{ns.Name, resource.GroupResource().String(), "<unknown>", getListErrorReason(err)})
+				continue
+			}
+
+			objects := getUnseenObjects(seen, list.Items)
+			if len(objects) == 0 {
+				continue
+			}
+
+			row := []string{ns.Name, resource.GroupResource().String(), strconv.Itoa(len(objects)), getFinalizerCounts(objects)}
+			remaining = append(remaining, row)
+		}
+	}
+
+	p, err := o.newPrinter(ctx, "", noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printTables(
+		resultTable{name: "namespaces", headers: []string{"NAME", "TERMINATING FOR", "FINALIZERS", "CONDITIONS"}, matrix: summary, uids: uids},
+		resultTable{name: "remaining", headers: []string{"NAMESPACE", "RESOURCE", "REMAINING", "FINALIZERS"}, matrix: remaining, numeric: sets.NewString("REMAINING"), optional: true},
+	)
+}
+
+// getNamespaceDeletionConditions returns the active conditions explaining why
+// the deletion of the Namespace is not complete, with their message.
+func getNamespaceDeletionConditions(ns corev1.Namespace) []string {
+	var conditions []string
+	for _, condition := range ns.Status.Conditions {
+		if namespaceDeletionConditions[condition.Type] && condition.Status == corev1.ConditionTrue {
+			conditions = append(conditions, fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+		}
+	}
+	return conditions
+}
+
+// getListErrorReason returns a short description of why a resource could not be listed.
+func getListErrorReason(err error) string {
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return fmt.Sprintf("<cannot list: %s>", reason)
+	}
+	return "<cannot list>"
+}
+
+// getFinalizerCounts returns the finalizers of the objects with the number of
+// objects holding each of them, sorted by finalizer.
+func getFinalizerCounts(objects []unstructured.Unstructured) string {
+	counts := make(map[string]int)
+	for _, obj := range objects {
+		for _, finalizer := range obj.GetFinalizers() {
+			counts[finalizer]++
+		}
+	}
+
+	if len(counts) == 0 {
+		return "<none>"
+	}
+
+	var finalizers []string
+	for finalizer, count := range counts {
+		finalizers = append(finalizers, fmt.Sprintf("%s(%d)", finalizer, count))
+	}
+	sort.Strings(finalizers)
+
+	return strings.Join(finalizers, ",")
+}
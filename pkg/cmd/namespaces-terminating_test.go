@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetNamespaceDeletionConditions(t *testing.T) {
+	t.Parallel()
+
+	ns := corev1.Namespace{Status: corev1.NamespaceStatus{
+		Phase: corev1.NamespaceTerminating,
+		Conditions: []corev1.NamespaceCondition{
+			{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionFalse, Message: "All resources successfully discovered"},
+			{Type: corev1.NamespaceDeletionContentFailure, Status: corev1.ConditionTrue, Message: "Failed to delete all resource types, 1 remaining"},
+			{Type: corev1.NamespaceFinalizersRemaining, Status: corev1.ConditionTrue, Message: "Some content in the namespace has finalizers remaining: example.com/cleanup in 2 resource instances"},
+		},
+	}}
+
+	want := []string{
+		"NamespaceDeletionContentFailure: Failed to delete all resource types, 1 remaining",
+		"NamespaceFinalizersRemaining: Some content in the namespace has finalizers remaining: example.com/cleanup in 2 resource instances",
+	}
+	assert.Equal(t, want, getNamespaceDeletionConditions(ns))
+}
+
+func TestGetFinalizerCounts(t *testing.T) {
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+	tests := []struct {
+		name    string
+		objects []unstructured.Unstructured
+		want    string
+	}{
+		{
+			name:    "objects without finalizers are expected to show none",
+			objects: []unstructured.Unstructured{newTestObject(widget, testNamespace, "a")},
+			want:    "<none>",
+		},
+		{
+			name:    "finalizers are expected to be counted and sorted",
+			objects: []unstructured.Unstructured{newTestObject(widget, testNamespace, "a", "example.com/cleanup"), newTestObject(widget, testNamespace, "b", "example.com/cleanup", "kubernetes.io/pvc-protection")},
+			want:    "example.com/cleanup(2),kubernetes.io/pvc-protection(1)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getFinalizerCounts(tt.objects))
+		})
+	}
+}
+
+func TestTerminatingNamespacesRun(t *testing.T) {
+	deleted := metav1.NewTime(time.Now().Add(-time.Hour))
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", DeletionTimestamp: &deleted},
+		Spec:       corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+
+	// The same Event is served by the core and events.k8s.io groups.
+	event := newTestObject(schema.GroupVersionKind{Version: "v1", Kind: "Event"}, "shop", "web.1", "example.com/cleanup")
+	newEvent := newTestObject(schema.GroupVersionKind{Group: "events.k8s.io", Version: "v1", Kind: "Event"}, "shop", "web.1", "example.com/cleanup")
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &event, &newEvent)
+	dynamicClient.PrependReactor("list", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(action.GetResource().GroupResource(), "", nil)
+	})
+
+	verbs := []string{"list"}
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "events", Kind: "Event", Namespaced: true, Verbs: verbs}}},
+		{GroupVersion: "events.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "events", Kind: "Event", Namespaced: true, Verbs: verbs}}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: verbs}}},
+	}}}
+
+	options, _, out, _ := newFakeJanitorOptions(fake.NewSimpleClientset(&ns))
+	options.dynamicClient = dynamicClient
+	options.discoveryClient = discoveryClient
+
+	o := newTerminatingNamespacesOptions(options)
+	o.olderThan = 10 * time.Minute
+
+	err := o.Run(context.Background(), true)
+	assert.NoError(t, err)
+
+	// The remaining resources are printed after the Namespaces, separated by an empty line.
+	tables := strings.Split(strings.TrimSpace(out.String()), "\n\n")
+	assert.Len(t, tables, 2)
+
+	var remaining [][]string
+	for _, line := range strings.Split(tables[len(tables)-1], "\n") {
+		remaining = append(remaining, strings.Fields(line))
+	}
+
+	// The Event is counted once, through whichever group was walked first.
+	assert.Len(t, remaining, 2)
+	assert.Contains(t, remaining, []string{"shop", "widgets.example.com", "<unknown>", "<cannot", "list:", "Forbidden>"})
+	for _, row := range remaining {
+		if strings.HasPrefix(row[1], "events") {
+			assert.Equal(t, []string{"1", "example.com/cleanup(1)"}, row[2:])
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newNamespacesCommand provides the base command when called without any subcommands.
+func newNamespacesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "namespaces",
+		Short:        "Find Namespaces in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newTerminatingNamespacesCommand(factory, options))
+
+	return cmd
+}
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	drainVerdictOK            = "OK"
+	drainVerdictRequiresFlags = "RequiresFlags"
+	drainVerdictBlocked       = "Blocked"
+
+	// annotationMirrorPod marks the mirror Pods of static Pods, which are skipped by kubectl drain.
+	annotationMirrorPod = "kubernetes.io/config.mirror"
+)
+
+// DrainCheckNodesOptions embeds JanitorOptions struct.
+type DrainCheckNodesOptions struct {
+	JanitorOptions
+}
+
+// drainBlocker describes why a Pod would prevent or complicate draining its Node.
+type drainBlocker struct {
+	pod      corev1.Pod
+	reason   string
+	flag     string
+	blocking bool
+}
+
+// newDrainCheckNodesOptions creates an instance of DrainCheckNodesOptions.
+func newDrainCheckNodesOptions(options JanitorOptions) *DrainCheckNodesOptions {
+	return &DrainCheckNodesOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newDrainCheckNodesCommand returns a cobra command wrapping DrainCheckNodesOptions.
+func newDrainCheckNodesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newDrainCheckNodesOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "drain-check NODE...",
+		Short:        "Predict what would block kubectl drain on the given Nodes",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, args, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// Run predicts the blockers of draining the given Nodes.
+func (o *DrainCheckNodesOptions) Run(ctx context.Context, names []string, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	pdbs, err := client.PolicyV1beta1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	draining := make(map[string]bool)
+	for _, name := range names {
+		draining[name] = true
+	}
+
+	nodeUID := make(map[string]types.UID)
+	var candidates []corev1.Node
+	for _, node := range nodes.Items {
+		if draining[node.Name] {
+			nodeUID[node.Name] = node.UID
+			continue
+		}
+		if !node.Spec.Unschedulable && isNodeReady(node) {
+			candidates = append(candidates, node)
+		}
+	}
+
+	podsByNode := getPodsByNode(pods.Items)
+
+	var summary, details [][]string
+	var nodeUIDs, podUIDs []types.UID
+
+	for _, name := range names {
+		if _, ok := nodeUID[name]; !ok {
+			return fmt.Errorf("node %q not found", name)
+		}
+		nodeUIDs = append(nodeUIDs, nodeUID[name])
+
+		blockers := getDrainBlockers(podsByNode[name], pdbs.Items, candidates, podsByNode)
+		verdict := getDrainVerdict(blockers)
+		summary = append(summary, []string{name, verdict, strconv.Itoa(len(podsByNode[name])), strconv.Itoa(len(blockers))})
+
+		for _, blocker := range blockers {
+			flag := blocker.flag
+			if flag == "" {
+				flag = "<none>"
+			}
+			podUIDs = append(podUIDs, blocker.pod.UID)
+			details = append(details, []string{name, blocker.pod.Namespace, blocker.pod.Name, blocker.reason, flag})
+		}
+	}
+
+	p, err := o.newPrinter(ctx, "", noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printTables(
+		resultTable{name: "nodes", headers: []string{"NODE", "VERDICT", "PODS", "BLOCKERS"}, matrix: summary, uids: nodeUIDs, numeric: sets.NewString("PODS", "BLOCKERS")},
+		resultTable{name: "blockers", headers: []string{"NODE", "NAMESPACE", "POD", "REASON", "DRAIN FLAG"}, matrix: details, uids: podUIDs, optional: true},
+	)
+}
+
+// isNodeReady checks if the Node is ready.
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// getDrainVerdict summarizes the blockers of a Node into a single verdict.
+func getDrainVerdict(blockers []drainBlocker) string {
+	verdict := drainVerdictOK
+	for _, blocker := range blockers {
+		if blocker.blocking {
+			return drainVerdictBlocked
+		}
+		verdict = drainVerdictRequiresFlags
+	}
+	return verdict
+}
+
+// getDrainBlockers returns what would block or complicate the eviction of the Pods
+// of a Node. The candidates are the Nodes that can receive the replacement Pods.
+// Each Pod is checked independently, so the capacity of the candidates is not
+// reduced by the other Pods being moved.
+func getDrainBlockers(pods []corev1.Pod, pdbs []policyv1beta1.PodDisruptionBudget, candidates []corev1.Node, podsByNode map[string][]corev1.Pod) []drainBlocker {
+	var blockers []drainBlocker
+
+	for _, pod := range pods {
+		if _, ok := pod.Annotations[annotationMirrorPod]; ok {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		owner := metav1.GetControllerOf(&pod)
+		if owner != nil && owner.Kind == "DaemonSet" {
+			blockers = append(blockers, drainBlocker{pod: pod, reason: "DaemonSetPod", flag: "--ignore-daemonsets"})
+			continue
+		}
+
+		if owner == nil {
+			blockers = append(blockers, drainBlocker{pod: pod, reason: "NoController", flag: "--force"})
+		}
+
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				blockers = append(blockers, drainBlocker{pod: pod, reason: fmt.Sprintf("LocalStorage(%s)", volume.Name), flag: "--delete-emptydir-data"})
+				break
+			}
+		}
+
+		for _, pdb := range getMatchingPDBs(pod, pdbs) {
+			if pdb.Status.DisruptionsAllowed == 0 {
+				blockers = append(blockers, drainBlocker{pod: pod, reason: fmt.Sprintf("PodDisruptionBudget(%s, 0 disruptions allowed)", pdb.Name), blocking: true})
+			}
+		}
+
+		if owner != nil && !canScheduleElsewhere(pod, candidates, podsByNode) {
+			blockers = append(blockers, drainBlocker{pod: pod, reason: "ReplacementUnschedulable", blocking: true})
+		}
+	}
+
+	return blockers
+}
+
+// canScheduleElsewhere checks whether one of the candidate Nodes could run a
+// replacement of the Pod, considering its nodeSelector, required node affinity,
+// tolerations and requests.
+func canScheduleElsewhere(pod corev1.Pod, candidates []corev1.Node, podsByNode map[string][]corev1.Pod) bool {
+	for _, node := range candidates {
+		if canScheduleOnNode(pod, node, podsByNode[node.Name]) {
+			return true
+		}
+	}
+	return false
+}
+
+// canScheduleOnNode checks whether the Pod fits on the Node, given the Pods already bound to it.
+func canScheduleOnNode(pod corev1.Pod, node corev1.Node, nodePods []corev1.Pod) bool {
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if !matchesRequiredNodeAffinity(pod, node) {
+		return false
+	}
+
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerated := false
+		for j := range pod.Spec.Tolerations {
+			if pod.Spec.Tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+
+	requests := getPodRequests(pod)
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, ok := requests[name]
+		if !ok {
+			continue
+		}
+
+		allocatable, ok := node.Status.Allocatable[name]
+		if !ok {
+			continue
+		}
+
+		used := resource.NewQuantity(0, allocatable.Format)
+		for _, p := range nodePods {
+			if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			if q, ok := getPodRequests(p)[name]; ok {
+				used.Add(q)
+			}
+		}
+
+		used.Add(request)
+		if used.Cmp(allocatable) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesRequiredNodeAffinity checks the Node against the Pod's
+// requiredDuringSchedulingIgnoredDuringExecution node affinity: the Node has to
+// match at least one of the terms, and a term matches when all of its
+// expressions and fields do.
+func matchesRequiredNodeAffinity(pod corev1.Pod, node corev1.Node) bool {
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		expressions, err := getNodeSelectorRequirements(term.MatchExpressions)
+		if err != nil || !expressions.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		// metadata.name is the only field supported by node affinity.
+		fields, err := getNodeSelectorRequirements(term.MatchFields)
+		if err != nil || !fields.Matches(labels.Set{"metadata.name": node.Name}) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// getNodeSelectorRequirements converts node selector requirements to a label selector.
+func getNodeSelectorRequirements(requirements []corev1.NodeSelectorRequirement) (labels.Selector, error) {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := operators[requirement.Operator]
+		if !ok {
+			return nil, fmt.Errorf("invalid node selector operator %q", requirement.Operator)
+		}
+
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*r)
+	}
+
+	return selector, nil
+}
+
+// getPodRequests returns the effective resource requests of a Pod: the sum of
+// its containers, or the largest init container request when it is higher.
+func getPodRequests(pod corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; ok {
+				total.Add(quantity)
+				requests[name] = total
+			} else {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; !ok || quantity.Cmp(total) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	return requests
+}
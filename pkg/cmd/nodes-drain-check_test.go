@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDrainBlockers(t *testing.T) {
+	requests := withRequests(newTestResources("500m", ""))
+	api := newTestPod(testNamespace, "api", onNode("node-1"), requests, ownedBy("ReplicaSet"))
+
+	withEmptyDir := newTestPod(testNamespace, "cache", onNode("node-1"), requests, ownedBy("ReplicaSet"))
+	withEmptyDir.Spec.Volumes = []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+
+	mirror := newTestPod(testNamespace, "static", onNode("node-1"), requests)
+	mirror.Annotations = map[string]string{annotationMirrorPod: "abc"}
+
+	pinned := newTestPod(testNamespace, "pinned", onNode("node-1"), requests, ownedBy("ReplicaSet"))
+	pinned.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+
+	pdb := newTestPDB("web-pdb", map[string]string{"app": "web"}, 0)
+
+	roomy := newTestNode("node-2", newTestResources("1", ""))
+	tainted := newTestNode("node-3", newTestResources("4", ""))
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}}
+
+	tests := []struct {
+		name        string
+		pods        []corev1.Pod
+		candidates  []corev1.Node
+		wantReasons []string
+		wantVerdict string
+	}{
+		{
+			name:        "Pods managed by a controller with room elsewhere are expected to be drainable",
+			pods:        []corev1.Pod{api, mirror},
+			candidates:  []corev1.Node{roomy},
+			wantReasons: nil,
+			wantVerdict: drainVerdictOK,
+		},
+		{
+			name:        "DaemonSet, bare and emptyDir Pods are expected to require flags",
+			pods:        []corev1.Pod{newTestPod(testNamespace, "agent", onNode("node-1"), requests, ownedBy("DaemonSet")), newTestPod(testNamespace, "bare", onNode("node-1"), requests), withEmptyDir},
+			candidates:  []corev1.Node{roomy},
+			wantReasons: []string{"DaemonSetPod", "NoController", "LocalStorage(tmp)"},
+			wantVerdict: drainVerdictRequiresFlags,
+		},
+		{
+			name:        "Pod covered by a PodDisruptionBudget allowing no disruption is expected to block",
+			pods:        []corev1.Pod{newTestPod(testNamespace, "web", withLabels(map[string]string{"app": "web"}), onNode("node-1"), requests, ownedBy("ReplicaSet"))},
+			candidates:  []corev1.Node{roomy},
+			wantReasons: []string{"PodDisruptionBudget(web-pdb, 0 disruptions allowed)"},
+			wantVerdict: drainVerdictBlocked,
+		},
+		{
+			name:        "Pod that fits on no other Node is expected to block",
+			pods:        []corev1.Pod{api, pinned},
+			candidates:  []corev1.Node{newTestNode("node-2", newTestResources("250m", "")), tainted},
+			wantReasons: []string{"ReplacementUnschedulable", "ReplacementUnschedulable"},
+			wantVerdict: drainVerdictBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			blockers := getDrainBlockers(tt.pods, []policyv1beta1.PodDisruptionBudget{pdb}, tt.candidates, nil)
+
+			var reasons []string
+			for _, blocker := range blockers {
+				reasons = append(reasons, blocker.reason)
+			}
+			assert.Equal(t, tt.wantReasons, reasons)
+			assert.Equal(t, tt.wantVerdict, getDrainVerdict(blockers))
+		})
+	}
+}
+
+func TestCanScheduleOnNode(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			}},
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		},
+	}
+
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"zone": "a"}},
+		Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "batch", Effect: corev1.TaintEffectNoSchedule}}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		nodePods []corev1.Pod
+		affinity *corev1.NodeSelector
+		want     bool
+	}{
+		{
+			name: "Pod tolerating the taint with enough memory left is expected to fit",
+			want: true,
+		},
+		{
+			name:     "Pod is expected not to fit once the Node memory is requested by other Pods",
+			nodePods: []corev1.Pod{pod, pod},
+			want:     false,
+		},
+		{
+			name: "Pod requiring a zone the Node is in is expected to fit",
+			affinity: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+			}},
+			want: true,
+		},
+		{
+			name: "Pod requiring another zone is expected not to fit",
+			affinity: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a"}}}},
+			}},
+			want: false,
+		},
+		{
+			name: "Pod requiring another Node by name is expected not to fit",
+			affinity: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-b"}}}},
+			}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pod := pod
+			if tt.affinity != nil {
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: tt.affinity}}
+			}
+			assert.Equal(t, tt.want, canScheduleOnNode(pod, node, tt.nodePods))
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// nodeLeaseNamespace is the namespace holding the heartbeat Leases of the Nodes.
+const nodeLeaseNamespace = "kube-node-lease"
+
+// UnhealthyNodesOptions embeds JanitorOptions struct.
+type UnhealthyNodesOptions struct {
+	JanitorOptions
+	cordonedFor     time.Duration
+	leaseStaleAfter time.Duration
+}
+
+// newUnhealthyNodesOptions creates an instance of UnhealthyNodesOptions.
+func newUnhealthyNodesOptions(options JanitorOptions) *UnhealthyNodesOptions {
+	return &UnhealthyNodesOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newUnhealthyNodesCommand returns a cobra command wrapping UnhealthyNodesOptions.
+func newUnhealthyNodesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newUnhealthyNodesOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "unhealthy",
+		Short:        "List Nodes that are not ready, under pressure, cordoned for too long or not heartbeating",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.cordonedFor, "cordoned-for", 24*time.Hour, "List Nodes that have been cordoned for longer than this duration.")
+	cmd.Flags().DurationVar(&o.leaseStaleAfter, "lease-stale-after", time.Minute, "List Nodes whose heartbeat Lease was not renewed for longer than this duration.")
+
+	return cmd
+}
+
+// Run lists Nodes in an unhealthy state.
+func (o *UnhealthyNodesOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	leases, err := client.CoordinationV1().Leases(nodeLeaseNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	nodeLeases := make(map[string]*coordinationv1.Lease)
+	for i := range leases.Items {
+		nodeLeases[leases.Items[i].Name] = &leases.Items[i]
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	podsByNode := getPodsByNode(pods.Items)
+	now := time.Now()
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, node := range nodes.Items {
+		problems := getNodeProblems(node, nodeLeases[node.Name], o.cordonedFor, o.leaseStaleAfter, now)
+		if len(problems) == 0 {
+			continue
+		}
+
+		unhealthy := 0
+		for _, pod := range podsByNode[node.Name] {
+			if !isPodHealthy(pod) {
+				unhealthy++
+			}
+		}
+
+		age := getAge(node.CreationTimestamp)
+		row := []string{node.Name, strings.Join(problems, ","), strconv.Itoa(len(podsByNode[node.Name])), strconv.Itoa(unhealthy), age}
+		uids = append(uids, node.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "PROBLEMS", "PODS", "UNHEALTHY PODS", "AGE"}
+
+	p, err := o.newPrinter(ctx, "", noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printClusterResults(headers, matrix, uids, "PODS", "UNHEALTHY PODS"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPodsByNode groups the Pods by the Node they are bound to.
+func getPodsByNode(pods []corev1.Pod) map[string][]corev1.Pod {
+	grouped := make(map[string][]corev1.Pod)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			grouped[pod.Spec.NodeName] = append(grouped[pod.Spec.NodeName], pod)
+		}
+	}
+	return grouped
+}
+
+// getNodeProblems returns the reasons a Node is considered unhealthy.
+// The lease is the heartbeat Lease of the Node, if any.
+func getNodeProblems(node corev1.Node, lease *coordinationv1.Lease, cordonedFor, leaseStaleAfter time.Duration, now time.Time) []string {
+	var problems []string
+
+	hasReadyCondition := false
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeReady:
+			hasReadyCondition = true
+			switch condition.Status {
+			case corev1.ConditionFalse:
+				problems = append(problems, "NotReady")
+			case corev1.ConditionUnknown:
+				problems = append(problems, "Unknown")
+			}
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			if condition.Status == corev1.ConditionTrue {
+				problems = append(problems, string(condition.Type))
+			}
+		}
+	}
+	if !hasReadyCondition {
+		problems = append(problems, "Unknown")
+	}
+
+	if node.Spec.Unschedulable {
+		cordoned := getNodeCordonTime(node)
+		if cordoned.IsZero() || now.Sub(cordoned.Time) > cordonedFor {
+			problems = append(problems, "Cordoned")
+		}
+	}
+
+	if lease != nil && lease.Spec.RenewTime != nil && now.Sub(lease.Spec.RenewTime.Time) > leaseStaleAfter {
+		problems = append(problems, "StaleLease")
+	}
+
+	return problems
+}
+
+// getNodeCordonTime approximates when the Node was cordoned, using the time the
+// unschedulable taint was added or the last update of spec.unschedulable
+// recorded in its managed fields.
+func getNodeCordonTime(node corev1.Node) metav1.Time {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable && taint.TimeAdded != nil {
+			return *taint.TimeAdded
+		}
+	}
+
+	var latest metav1.Time
+	for _, entry := range node.ManagedFields {
+		if entry.Time == nil || entry.FieldsV1 == nil || !bytes.Contains(entry.FieldsV1.Raw, []byte(`"f:unschedulable"`)) {
+			continue
+		}
+		if latest.Before(entry.Time) {
+			latest = *entry.Time
+		}
+	}
+	return latest
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetNodeProblems(t *testing.T) {
+	now := time.Now()
+
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+
+	cordonedLongAgo := newTestNode("node-1", nil, ready)
+	cordonedLongAgo.Spec.Unschedulable = true
+	cordonedLongAgo.Spec.Taints = []corev1.Taint{{
+		Key:       corev1.TaintNodeUnschedulable,
+		Effect:    corev1.TaintEffectNoSchedule,
+		TimeAdded: &metav1.Time{Time: now.Add(-48 * time.Hour)},
+	}}
+
+	cordonedRecently := newTestNode("node-1", nil, ready)
+	cordonedRecently.Spec.Unschedulable = true
+	cordonedRecently.ManagedFields = []metav1.ManagedFieldsEntry{{
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      &metav1.Time{Time: now.Add(-time.Hour)},
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:unschedulable":{}}}`)},
+	}}
+
+	tests := []struct {
+		name  string
+		node  corev1.Node
+		lease *coordinationv1.Lease
+		want  []string
+	}{
+		{
+			name: "Ready Node is expected to be healthy",
+			node: newTestNode("node-1", nil, ready),
+			want: nil,
+		},
+		{
+			name: "NotReady Node under memory pressure is expected to be reported",
+			node: newTestNode("node-1", nil,
+				corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+				corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+			),
+			want: []string{"NotReady", "MemoryPressure"},
+		},
+		{
+			name: "Node with an Unknown Ready condition is expected to be reported",
+			node: newTestNode("node-1", nil, corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}),
+			want: []string{"Unknown"},
+		},
+		{
+			name: "Node cordoned for two days is expected to be reported",
+			node: cordonedLongAgo,
+			want: []string{"Cordoned"},
+		},
+		{
+			name: "Node cordoned an hour ago is not expected to be reported",
+			node: cordonedRecently,
+			want: nil,
+		},
+		{
+			name: "Node with a stale Lease is expected to be reported",
+			node: newTestNode("node-1", nil, ready),
+			lease: &coordinationv1.Lease{
+				Spec: coordinationv1.LeaseSpec{RenewTime: &metav1.MicroTime{Time: now.Add(-5 * time.Minute)}},
+			},
+			want: []string{"StaleLease"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getNodeProblems(tc.node, tc.lease, 24*time.Hour, time.Minute, now)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
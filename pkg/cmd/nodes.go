@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newNodesCommand provides the base command when called without any subcommands.
+func newNodesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "nodes",
+		Short:        "Find Nodes in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newUnhealthyNodesCommand(factory, options))
+	cmd.AddCommand(newDrainCheckNodesCommand(factory, options))
+
+	return cmd
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
@@ -17,6 +21,16 @@ type JanitorOptions struct {
 	ResourceBuilderFlags *genericclioptions.ResourceBuilderFlags
 	namespace            string
 	allNamespaces        bool
+	output               string
+	events               int
+	configPath           string
+
+	// client, dynamicClient and discoveryClient replace the clients built
+	// from ConfigFlags when set, which the tests use to run the commands
+	// against fake clusters.
+	client          kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
 }
 
 // NewJanitorOptions provides an instance of JanitorOptions with default values.
@@ -35,7 +49,11 @@ func NewJanitorOptions() JanitorOptions {
 	}
 }
 
-func (o *JanitorOptions) GetClient() (*kubernetes.Clientset, error) {
+func (o *JanitorOptions) GetClient() (kubernetes.Interface, error) {
+	if o.client != nil {
+		return o.client, nil
+	}
+
 	restConfig, err := o.ConfigFlags.ToRESTConfig()
 	if err != nil {
 		return nil, err
@@ -49,6 +67,29 @@ func (o *JanitorOptions) GetClient() (*kubernetes.Clientset, error) {
 
 }
 
+// GetDynamicClient returns a client able to work with any resource served by the cluster.
+func (o *JanitorOptions) GetDynamicClient() (dynamic.Interface, error) {
+	if o.dynamicClient != nil {
+		return o.dynamicClient, nil
+	}
+
+	restConfig, err := o.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(restConfig)
+}
+
+// GetDiscoveryClient returns a client listing the resources served by the cluster.
+func (o *JanitorOptions) GetDiscoveryClient() (discovery.DiscoveryInterface, error) {
+	if o.discoveryClient != nil {
+		return o.discoveryClient, nil
+	}
+
+	return o.ConfigFlags.ToDiscoveryClient()
+}
+
 // Complete sets all information required for working with Kubernetes.
 func (o *JanitorOptions) Complete(factory cmdutil.Factory, cmd *cobra.Command) error {
 	var err error
@@ -57,10 +98,30 @@ func (o *JanitorOptions) Complete(factory cmdutil.Factory, cmd *cobra.Command) e
 		return err
 	}
 
-	if cmd.Flag("all-namespaces").Changed {
+	if flag := cmd.Flag("all-namespaces"); flag != nil && flag.Changed {
 		o.allNamespaces = *o.ResourceBuilderFlags.AllNamespaces
 		o.namespace = ""
 	}
 
+	if flag := cmd.Flag("output"); flag != nil {
+		o.output = flag.Value.String()
+		switch o.output {
+		case "", outputWide, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("invalid --output %q: must be one of wide, json or yaml", o.output)
+		}
+	}
+
+	if flag := cmd.Flag("janitor-config"); flag != nil {
+		o.configPath = flag.Value.String()
+	}
+
+	if flag := cmd.Flag("events"); flag != nil {
+		o.events, err = strconv.Atoi(flag.Value.String())
+		if err != nil || o.events < 0 {
+			return fmt.Errorf("invalid --events %q: must be a positive number", flag.Value.String())
+		}
+	}
+
 	return nil
 }
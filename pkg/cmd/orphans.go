@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// OrphansOptions embeds JanitorOptions struct.
+type OrphansOptions struct {
+	JanitorOptions
+}
+
+// orphan is an object whose owner no longer exists, or a Pod without any owner.
+type orphan struct {
+	resource apiResource
+	obj      unstructured.Unstructured
+	owner    metav1.OwnerReference
+}
+
+// newOrphansOptions creates an instance of OrphansOptions.
+func newOrphansOptions(options JanitorOptions) *OrphansOptions {
+	return &OrphansOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newOrphansCommand returns a cobra command wrapping OrphansOptions.
+func newOrphansCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newOrphansOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "orphans",
+		Short:        "List objects whose owners no longer exist and Pods without any owner",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run lists the objects with dangling ownerReferences and the naked Pods.
+// Owners missing from the lists are fetched again before they are reported.
+// Cluster-scoped resources are always walked to find owners, but their objects
+// are only reported with --all-namespaces.
+func (o *OrphansOptions) Run(ctx context.Context, noHeader bool) error {
+	discoveryClient, err := o.GetDiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	resources, err := getServedResources(discoveryClient, false)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[types.UID]bool)
+	listedKinds := make(map[schema.GroupKind]bool)
+	kindResources := make(map[schema.GroupKind]apiResource)
+	objects := make(map[apiResource][]unstructured.Unstructured)
+	var pods []unstructured.Unstructured
+
+	for _, resource := range resources {
+		client := dynamicClient.Resource(resource.GroupVersionResource)
+
+		var list *unstructured.UnstructuredList
+		if resource.namespaced {
+			list, err = client.Namespace(o.namespace).List(ctx, metav1.ListOptions{})
+		} else {
+			list, err = client.List(ctx, metav1.ListOptions{})
+		}
+		if err != nil {
+			// Owners of a kind that cannot be listed are never reported as missing.
+			continue
+		}
+
+		gk := schema.GroupKind{Group: resource.Group, Kind: resource.kind}
+		listedKinds[gk] = true
+		kindResources[gk] = resource
+
+		unseen := getUnseenObjects(existing, list.Items)
+		if resource.namespaced || o.allNamespaces {
+			objects[resource] = unseen
+		}
+		if resource.Group == "" && resource.Resource == "pods" {
+			pods = list.Items
+		}
+	}
+
+	var orphans []orphan
+	var errs []error
+	missing := make(map[types.UID]bool)
+
+	for resource, items := range objects {
+		for _, obj := range items {
+			for _, owner := range getDanglingOwners(obj, existing, listedKinds) {
+				gone, checked := missing[owner.UID]
+				if !checked {
+					ownerResource := kindResources[schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).GroupKind()]
+					gone, err = isOwnerGone(ctx, dynamicClient, ownerResource, obj.GetNamespace(), owner)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to get %s/%s: %v", ownerResource.GroupResource(), owner.Name, err))
+						continue
+					}
+					missing[owner.UID] = gone
+				}
+				if gone {
+					orphans = append(orphans, orphan{resource: resource, obj: obj, owner: owner})
+				}
+			}
+		}
+	}
+
+	podsResource := apiResource{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, kind: "Pod", namespaced: true}
+	for _, pod := range getNakedPods(pods) {
+		orphans = append(orphans, orphan{resource: podsResource, obj: pod})
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		a, b := orphans[i], orphans[j]
+		if a.obj.GetNamespace() != b.obj.GetNamespace() {
+			return a.obj.GetNamespace() < b.obj.GetNamespace()
+		}
+		if ra, rb := a.resource.GroupResource().String(), b.resource.GroupResource().String(); ra != rb {
+			return ra < rb
+		}
+		return a.obj.GetName() < b.obj.GetName()
+	})
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, orphan := range orphans {
+		ownerKind, ownerName, ownerUID := "<none>", "<none>", "<none>"
+		if orphan.owner.UID != "" {
+			ownerKind, ownerName, ownerUID = orphan.owner.Kind, orphan.owner.Name, string(orphan.owner.UID)
+		}
+
+		age := getAge(orphan.obj.GetCreationTimestamp())
+		row := []string{orphan.resource.GroupResource().String() + "/" + orphan.obj.GetName(), ownerKind, ownerName, ownerUID, age}
+		if o.allNamespaces {
+			namespace := orphan.obj.GetNamespace()
+			if namespace == "" {
+				namespace = "<none>"
+			}
+			row = append([]string{namespace}, row...)
+		}
+		uids = append(uids, orphan.obj.GetUID())
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "OWNER KIND", "OWNER NAME", "OWNER UID", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// isOwnerGone gets the owner again before it is reported as missing, since the
+// resources are listed one after the other and the owner may have been created
+// after its kind was listed. An object with the same name but another UID
+// replaced the owner.
+func isOwnerGone(ctx context.Context, dynamicClient dynamic.Interface, resource apiResource, namespace string, owner metav1.OwnerReference) (bool, error) {
+	client := dynamicClient.Resource(resource.GroupVersionResource)
+
+	var u *unstructured.Unstructured
+	var err error
+	if resource.namespaced {
+		u, err = client.Namespace(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	} else {
+		u, err = client.Get(ctx, owner.Name, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return u.GetUID() != owner.UID, nil
+}
+
+// getDanglingOwners returns the ownerReferences of the object pointing to a UID
+// that does not exist. References to kinds that were not listed are ignored.
+func getDanglingOwners(obj unstructured.Unstructured, uids map[types.UID]bool, listedKinds map[schema.GroupKind]bool) []metav1.OwnerReference {
+	var dangling []metav1.OwnerReference
+	for _, owner := range obj.GetOwnerReferences() {
+		gk := schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).GroupKind()
+		if listedKinds[gk] && !uids[owner.UID] {
+			dangling = append(dangling, owner)
+		}
+	}
+	return dangling
+}
+
+// getNakedPods returns the Pods without any ownerReference that live in a
+// Namespace where other Pods are managed by a controller. Mirror Pods are ignored.
+func getNakedPods(pods []unstructured.Unstructured) []unstructured.Unstructured {
+	controlled := make(map[string]bool)
+	for i := range pods {
+		if metav1.GetControllerOf(&pods[i]) != nil {
+			controlled[pods[i].GetNamespace()] = true
+		}
+	}
+
+	var naked []unstructured.Unstructured
+	for _, pod := range pods {
+		if _, ok := pod.GetAnnotations()[annotationMirrorPod]; ok {
+			continue
+		}
+		if controlled[pod.GetNamespace()] && len(pod.GetOwnerReferences()) == 0 {
+			naked = append(naked, pod)
+		}
+	}
+	return naked
+}
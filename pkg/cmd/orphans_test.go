@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newOwnedObject(namespace, name string, owners ...metav1.OwnerReference) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetOwnerReferences(owners)
+	return obj
+}
+
+func TestGetDanglingOwners(t *testing.T) {
+	controller := true
+	existing := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-1", UID: "uid-1", Controller: &controller}
+	deleted := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-0", UID: "uid-0", Controller: &controller}
+	unlisted := metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "Widget", Name: "widget", UID: "uid-2"}
+
+	uids := map[types.UID]bool{"uid-1": true}
+	listedKinds := map[schema.GroupKind]bool{{Group: "apps", Kind: "ReplicaSet"}: true}
+
+	tests := []struct {
+		name string
+		obj  unstructured.Unstructured
+		want []metav1.OwnerReference
+	}{
+		{
+			name: "object owned by an existing owner is expected not to be reported",
+			obj:  newOwnedObject("default", "web-1-abcde", existing),
+			want: nil,
+		},
+		{
+			name: "object owned by a deleted owner is expected to be reported",
+			obj:  newOwnedObject("default", "web-0-abcde", deleted),
+			want: []metav1.OwnerReference{deleted},
+		},
+		{
+			name: "owner of a kind that was not listed is expected to be ignored",
+			obj:  newOwnedObject("default", "widget-config", unlisted),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getDanglingOwners(tt.obj, uids, listedKinds))
+		})
+	}
+}
+
+func TestGetNakedPods(t *testing.T) {
+	t.Parallel()
+
+	controller := true
+	owner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-1", UID: "uid-1", Controller: &controller}
+
+	mirror := newOwnedObject("default", "etcd")
+	mirror.SetAnnotations(map[string]string{annotationMirrorPod: "abc"})
+
+	pods := []unstructured.Unstructured{
+		newOwnedObject("default", "web-1-abcde", owner),
+		newOwnedObject("default", "debug"),
+		mirror,
+		newOwnedObject("sandbox", "scratch"),
+	}
+
+	var names []string
+	for _, pod := range getNakedPods(pods) {
+		names = append(names, pod.GetNamespace()+"/"+pod.GetName())
+	}
+	assert.Equal(t, []string{"default/debug"}, names)
+}
+
+func TestOrphansRun(t *testing.T) {
+	replicaSetKind := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}
+	deploymentKind := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	// The Deployment is created after the Deployments were listed.
+	deployment := newTestObject(deploymentKind, testNamespace, "web")
+	adopted := newTestObject(replicaSetKind, testNamespace, "web-abc")
+	adopted.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: deployment.GetUID()}})
+	orphaned := newTestObject(replicaSetKind, testNamespace, "api-abc")
+	orphaned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "api", UID: "api-uid"}})
+
+	// The same Ingress is served by the extensions and networking.k8s.io groups.
+	ingressOwner := []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "gone", UID: "gone-uid"}}
+	ingress := newTestObject(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, testNamespace, "web")
+	ingress.SetOwnerReferences(ingressOwner)
+	legacyIngress := newTestObject(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}, testNamespace, "web")
+	legacyIngress.SetOwnerReferences(ingressOwner)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &deployment, &adopted, &orphaned, &ingress, &legacyIngress)
+	dynamicClient.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(deploymentKind.GroupVersion().WithKind("DeploymentList"))
+		return true, list, nil
+	})
+
+	verbs := []string{"get", "list"}
+	options, _, out, _ := newFakeJanitorOptions(nil)
+	options.dynamicClient = dynamicClient
+	options.discoveryClient = &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: verbs},
+			{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: verbs},
+		}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true, Verbs: verbs}}},
+		{GroupVersion: "extensions/v1beta1", APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true, Verbs: verbs}}},
+	}}}
+	o := newOrphansOptions(options)
+
+	err := o.Run(context.Background(), true)
+	assert.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "replicasets.apps/api-abc")
+	assert.NotContains(t, output, "replicasets.apps/web-abc")
+	assert.Equal(t, 1, strings.Count(output, "gone-uid"))
+}
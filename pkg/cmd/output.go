@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputWide = "wide"
+	outputJSON = "json"
+	outputYAML = "yaml"
+
+	// defaultEvents is the number of Events attached to each finding when --events is given without a value.
+	defaultEvents = 3
+)
+
+// printer writes the results of a check in the format selected with --output.
+// With --events, the latest Events of every reported object are attached to
+// its row in the wide and structured formats.
+type printer struct {
+	out       io.Writer
+	format    string
+	noHeader  bool
+	maxEvents int
+	events    map[types.UID][]corev1.Event
+	tables    int
+}
+
+// resultEvent is an Event attached to a finding in the structured output.
+type resultEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// resultTable is a table of findings. The uids hold the UID of the object
+// reported by each row, or are nil when the rows are not about a single object.
+type resultTable struct {
+	// name is the field holding the rows of the table when a command prints
+	// several tables in a structured format.
+	name       string
+	headers    []string
+	matrix     [][]string
+	uids       []types.UID
+	namespaced bool
+	namespace  string
+	// numeric holds the headers of the columns holding counts, printed as
+	// numbers in the structured formats.
+	numeric sets.String
+	// optional tables are left out of the table formats when they have no rows.
+	optional bool
+}
+
+// resultDetails are extra data about the rows of a table, shown under each row
+// in the table formats and as a field of each item in the structured formats.
+type resultDetails struct {
+	key    string
+	lines  [][]string
+	values []interface{}
+}
+
+// newPrinter creates a printer for the output options of the command. The Events
+// of the namespace, or of all namespaces when empty, are fetched with --events.
+func (o *JanitorOptions) newPrinter(ctx context.Context, eventsNamespace string, noHeader bool) (*printer, error) {
+	p := &printer{
+		out:       o.Streams.Out,
+		format:    o.output,
+		noHeader:  noHeader,
+		maxEvents: o.events,
+	}
+
+	if p.maxEvents == 0 {
+		return p, nil
+	}
+
+	if p.format == "" {
+		p.format = outputWide
+	}
+
+	client, err := o.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	p.events, err = getEvents(ctx, client, eventsNamespace, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// printResults prints the findings about namespaced objects. The uids hold the
+// UID of the object reported by each row, or are nil when the rows are not about
+// a single object. The numeric headers are the columns holding counts.
+func (p *printer) printResults(headers []string, matrix [][]string, uids []types.UID, namespace string, numeric ...string) error {
+	return p.print(resultTable{headers: headers, matrix: matrix, uids: uids, namespaced: true, namespace: namespace, numeric: sets.NewString(numeric...)}, nil)
+}
+
+// printResultsWithDetails prints the findings about namespaced objects with
+// the details of every row.
+func (p *printer) printResultsWithDetails(headers []string, matrix [][]string, uids []types.UID, namespace string, details []resultDetails, numeric ...string) error {
+	return p.print(resultTable{headers: headers, matrix: matrix, uids: uids, namespaced: true, namespace: namespace, numeric: sets.NewString(numeric...)}, details)
+}
+
+// printClusterResults prints the findings about cluster-scoped objects.
+func (p *printer) printClusterResults(headers []string, matrix [][]string, uids []types.UID, numeric ...string) error {
+	return p.print(resultTable{headers: headers, matrix: matrix, uids: uids, numeric: sets.NewString(numeric...)}, nil)
+}
+
+// printTables prints the tables of a command reporting several of them. The
+// structured formats hold them in a single document, with the rows of every
+// table under its name.
+func (p *printer) printTables(tables ...resultTable) error {
+	if p.format != outputJSON && p.format != outputYAML {
+		for _, table := range tables {
+			if table.optional && len(table.matrix) == 0 {
+				continue
+			}
+			if err := p.print(table, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	document := make(map[string]interface{}, len(tables))
+	for _, table := range tables {
+		document[table.name] = p.getResultItems(table, nil)
+	}
+	return p.writeDocument(document)
+}
+
+func (p *printer) print(table resultTable, details []resultDetails) error {
+	if p.format == outputJSON || p.format == outputYAML {
+		return p.writeDocument(p.getResultItems(table, details))
+	}
+
+	p.tables++
+	if p.tables > 1 {
+		fmt.Fprintln(p.out)
+	}
+
+	headers, matrix, uids := table.headers, table.matrix, table.uids
+
+	if p.format == outputWide && p.maxEvents > 0 && uids != nil {
+		headers = append(append([]string{}, headers...), "EVENTS")
+		wide := make([][]string, 0, len(matrix))
+		for i, row := range matrix {
+			wide = append(wide, append(append([]string{}, row...), p.getEventsSummary(uids[i])))
+		}
+		matrix = wide
+	}
+
+	out := p.out
+	buf := bytes.NewBuffer(nil)
+	if len(details) > 0 {
+		out = buf
+	}
+
+	if table.namespaced {
+		writeResults(out, headers, matrix, table.namespace, p.noHeader)
+	} else {
+		writeClusterResults(out, headers, matrix, p.noHeader)
+	}
+
+	if len(details) > 0 {
+		lines := make([][]string, len(matrix))
+		for _, d := range details {
+			for i := range lines {
+				lines[i] = append(lines[i], d.lines[i]...)
+			}
+		}
+		writeDetails(p.out, buf.String(), len(matrix), lines)
+	}
+	return nil
+}
+
+// getResultItems returns the rows of the table for the structured formats,
+// with their Events and details.
+func (p *printer) getResultItems(table resultTable, details []resultDetails) []map[string]interface{} {
+	headers := table.headers
+	if table.namespaced && table.namespace == "" {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+
+	items := make([]map[string]interface{}, 0, len(table.matrix))
+	for i, row := range table.matrix {
+		item := make(map[string]interface{})
+		for j, header := range headers {
+			if j < len(row) {
+				item[getOutputKey(header)] = getOutputValue(row[j], table.numeric.Has(header))
+			}
+		}
+		if p.maxEvents > 0 && table.uids != nil {
+			item["events"] = p.getResultEvents(table.uids[i])
+		}
+		for _, d := range details {
+			item[d.key] = d.values[i]
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// writeDocument writes the results in the structured format.
+func (p *printer) writeDocument(document interface{}) error {
+	if p.format == outputJSON {
+		data, err := json.MarshalIndent(document, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(p.out, "%s\n", data)
+		return nil
+	}
+
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "%s", data)
+	return nil
+}
+
+// writeDetails copies the table to out with the detail lines of every row
+// indented under it. The rows are the last lines of the table.
+func writeDetails(out io.Writer, table string, rows int, lines [][]string) {
+	tableLines := strings.SplitAfter(table, "\n")
+	if n := len(tableLines); n > 0 && tableLines[n-1] == "" {
+		tableLines = tableLines[:n-1]
+	}
+
+	first := len(tableLines) - rows
+	for i, line := range tableLines {
+		fmt.Fprint(out, line)
+		if row := i - first; row >= 0 && row < len(lines) {
+			for _, detail := range lines[row] {
+				fmt.Fprintf(out, "    %s\n", detail)
+			}
+		}
+	}
+}
+
+// getResultEvents returns the latest Events of the object for the structured output.
+func (p *printer) getResultEvents(uid types.UID) []resultEvent {
+	events := []resultEvent{}
+	for i, event := range p.events[uid] {
+		if i == p.maxEvents {
+			break
+		}
+		events = append(events, resultEvent{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  strings.TrimSpace(event.Message),
+			Count:    event.Count,
+			LastSeen: getEventTime(event),
+		})
+	}
+	return events
+}
+
+// getEventsSummary returns the latest Events of the object on a single line for the wide output.
+func (p *printer) getEventsSummary(uid types.UID) string {
+	var summary []string
+	for i, event := range p.events[uid] {
+		if i == p.maxEvents {
+			break
+		}
+		age := duration.HumanDuration(time.Since(getEventTime(event)))
+		message := strings.Join(strings.Fields(event.Message), " ")
+		summary = append(summary, fmt.Sprintf("%s (%s ago): %s", event.Reason, age, message))
+	}
+
+	if len(summary) == 0 {
+		return "<none>"
+	}
+	return strings.Join(summary, "; ")
+}
+
+// getOutputKey converts a column header to a field name of the structured output,
+// for example "FORMER CLAIM" to "formerClaim".
+func getOutputKey(header string) string {
+	words := strings.FieldsFunc(strings.ToLower(header), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// getOutputValue converts a cell to a value of the structured output. The cells
+// of numeric columns are numbers, and null when they hold a placeholder such as
+// <none>; the other cells are strings.
+func getOutputValue(value string, numeric bool) interface{} {
+	if !numeric {
+		return value
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGetOutputKey(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "NAME", want: "name"},
+		{header: "FORMER CLAIM", want: "formerClaim"},
+		{header: "TERMINATING FOR", want: "terminatingFor"},
+		{header: "LAST SEEN", want: "lastSeen"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getOutputKey(tt.header))
+		})
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	lastSeen := metav1.NewTime(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+	events := map[types.UID][]corev1.Event{
+		"uid-a": {
+			{Type: corev1.EventTypeWarning, Reason: "FailedScheduling", Message: "0/3 nodes are available", Count: 4, LastTimestamp: lastSeen},
+			{Type: corev1.EventTypeNormal, Reason: "Scheduled", Message: "assigned", Count: 1, LastTimestamp: lastSeen},
+		},
+	}
+
+	headers := []string{"NAME", "REASON"}
+	matrix := [][]string{{"pod-a", "Unschedulable"}, {"pod-b", "Unschedulable"}}
+	uids := []types.UID{"uid-a", "uid-b"}
+
+	tests := []struct {
+		name      string
+		format    string
+		maxEvents int
+		uids      []types.UID
+		tables    int
+		want      string
+	}{
+		{
+			name:   "Default output is a table",
+			format: "",
+			uids:   uids,
+			tables: 1,
+			want: "NAME    REASON\n" +
+				"pod-a   Unschedulable\n" +
+				"pod-b   Unschedulable\n",
+		},
+		{
+			name:   "Tables are separated by a blank line",
+			format: "",
+			uids:   uids,
+			tables: 2,
+			want: "NAME    REASON\n" +
+				"pod-a   Unschedulable\n" +
+				"pod-b   Unschedulable\n" +
+				"\n" +
+				"NAME    REASON\n" +
+				"pod-a   Unschedulable\n" +
+				"pod-b   Unschedulable\n",
+		},
+		{
+			name:      "Wide output is expected to attach the latest Events",
+			format:    outputWide,
+			maxEvents: 1,
+			uids:      uids,
+			tables:    1,
+			want: "NAME    REASON          EVENTS\n" +
+				"pod-a   Unschedulable   FailedScheduling (" + getAge(lastSeen) + " ago): 0/3 nodes are available\n" +
+				"pod-b   Unschedulable   <none>\n",
+		},
+		{
+			name:      "Wide output of rows not about an object has no Events",
+			format:    outputWide,
+			maxEvents: 1,
+			uids:      nil,
+			tables:    1,
+			want: "NAME    REASON\n" +
+				"pod-a   Unschedulable\n" +
+				"pod-b   Unschedulable\n",
+		},
+		{
+			name:      "JSON output is expected to list the latest Events",
+			format:    outputJSON,
+			maxEvents: 1,
+			uids:      uids,
+			tables:    1,
+			want: `[
+    {
+        "events": [
+            {
+                "type": "Warning",
+                "reason": "FailedScheduling",
+                "message": "0/3 nodes are available",
+                "count": 4,
+                "lastSeen": "2021-01-01T00:00:00Z"
+            }
+        ],
+        "name": "pod-a",
+        "reason": "Unschedulable"
+    },
+    {
+        "events": [],
+        "name": "pod-b",
+        "reason": "Unschedulable"
+    }
+]
+`,
+		},
+		{
+			name:   "YAML output is a list of rows",
+			format: outputYAML,
+			uids:   uids,
+			tables: 1,
+			want: "- name: pod-a\n" +
+				"  reason: Unschedulable\n" +
+				"- name: pod-b\n" +
+				"  reason: Unschedulable\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := &bytes.Buffer{}
+			p := &printer{out: out, format: tt.format, maxEvents: tt.maxEvents, events: events}
+			for i := 0; i < tt.tables; i++ {
+				err := p.printClusterResults(headers, matrix, tt.uids)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestPrinterTables(t *testing.T) {
+	tables := []resultTable{
+		{name: "volumes", headers: []string{"NAME", "CAPACITY"}, matrix: [][]string{{"pv-a", "10Gi"}}},
+		{name: "wastedCapacity", headers: []string{"STORAGECLASS", "VOLUMES"}, matrix: [][]string{{"standard", "1"}}, numeric: sets.NewString("VOLUMES"), optional: true},
+		{name: "blockers", headers: []string{"POD", "RESTARTS"}, matrix: nil, optional: true},
+	}
+
+	t.Run("Table output is expected to leave out the empty optional tables", func(t *testing.T) {
+		t.Parallel()
+
+		out := &bytes.Buffer{}
+		p := &printer{out: out}
+		assert.NoError(t, p.printTables(tables...))
+		assert.Equal(t, "NAME   CAPACITY\n"+
+			"pv-a   10Gi\n"+
+			"\n"+
+			"STORAGECLASS   VOLUMES\n"+
+			"standard       1\n", out.String())
+	})
+
+	want := map[string]interface{}{
+		"volumes":        []interface{}{map[string]interface{}{"name": "pv-a", "capacity": "10Gi"}},
+		"wastedCapacity": []interface{}{map[string]interface{}{"storageclass": "standard", "volumes": float64(1)}},
+		"blockers":       []interface{}{},
+	}
+
+	t.Run("JSON output is expected to be a single document with every table", func(t *testing.T) {
+		t.Parallel()
+
+		out := &bytes.Buffer{}
+		p := &printer{out: out, format: outputJSON}
+		assert.NoError(t, p.printTables(tables...))
+
+		var got map[string]interface{}
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("YAML output is expected to be a single document with every table", func(t *testing.T) {
+		t.Parallel()
+
+		out := &bytes.Buffer{}
+		p := &printer{out: out, format: outputYAML}
+		assert.NoError(t, p.printTables(tables...))
+
+		var got map[string]interface{}
+		assert.NoError(t, yaml.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestGetOutputValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		numeric bool
+		want    interface{}
+	}{
+		{value: "3", numeric: false, want: "3"},
+		{value: "3", numeric: true, want: 3},
+		{value: "<none>", numeric: true, want: nil},
+		{value: "2/5", numeric: true, want: "2/5"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(fmt.Sprintf("%s numeric=%t", tt.value, tt.numeric), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getOutputValue(tt.value, tt.numeric))
+		})
+	}
+}
+
+func TestWriteDetails(t *testing.T) {
+	table := "NAME    STATUS\n" +
+		"pod-a   Error\n" +
+		"pod-b   Pending\n"
+
+	tests := []struct {
+		name  string
+		table string
+		rows  int
+		lines [][]string
+		want  string
+	}{
+		{
+			name:  "Detail lines are expected to be indented under their row",
+			table: table,
+			rows:  2,
+			lines: [][]string{{"app: Error", "  panic"}, nil},
+			want: "NAME    STATUS\n" +
+				"pod-a   Error\n" +
+				"    app: Error\n" +
+				"      panic\n" +
+				"pod-b   Pending\n",
+		},
+		{
+			name:  "Table without header is expected to keep the rows aligned with their details",
+			table: "pod-a   Error\n",
+			rows:  1,
+			lines: [][]string{{"app: Error"}},
+			want:  "pod-a   Error\n    app: Error\n",
+		},
+		{
+			name:  "Empty table is expected to be copied unchanged",
+			table: "No resources found\n",
+			rows:  0,
+			want:  "No resources found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := &bytes.Buffer{}
+			writeDetails(out, tt.table, tt.rows, tt.lines)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
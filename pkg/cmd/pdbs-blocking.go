@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// pdbsResource is the resource of the PodDisruptionBudgets.
+var pdbsResource = schema.GroupVersionResource{Group: "policy", Version: "v1beta1", Resource: "poddisruptionbudgets"}
+
+// BlockingPDBsOptions embeds JanitorOptions struct.
+type BlockingPDBsOptions struct {
+	JanitorOptions
+	blockedFor time.Duration
+}
+
+// newBlockingPDBsOptions creates an instance of BlockingPDBsOptions.
+func newBlockingPDBsOptions(options JanitorOptions) *BlockingPDBsOptions {
+	return &BlockingPDBsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newBlockingPDBsCommand returns a cobra command wrapping BlockingPDBsOptions.
+func newBlockingPDBsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newBlockingPDBsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "blocking",
+		Short:        "List PodDisruptionBudgets that block evictions or are misconfigured",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.blockedFor, "blocked-for", time.Hour, "List PodDisruptionBudgets that have allowed no disruption for longer than this duration.")
+
+	return cmd
+}
+
+// Run lists PodDisruptionBudgets that block evictions or are misconfigured.
+func (o *BlockingPDBsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := o.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	// The PodDisruptionBudgets are listed unstructured to read the DisruptionAllowed
+	// condition, which clusters since Kubernetes 1.20 set but the typed API lacks.
+	list, err := dynamicClient.Resource(pdbsResource).Namespace(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var pdbs []policyv1beta1.PodDisruptionBudget
+	allowedSince := make(map[types.UID]metav1.Time)
+	for _, item := range list.Items {
+		var pdb policyv1beta1.PodDisruptionBudget
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pdb); err != nil {
+			return err
+		}
+		pdbs = append(pdbs, pdb)
+		allowedSince[pdb.UID] = getDisruptionAllowedTransitionTime(item)
+	}
+
+	pods, err := client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	covered, overlaps := getPDBCoverage(pods.Items, pdbs)
+	now := time.Now()
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, pdb := range pdbs {
+		nn := types.NamespacedName{Namespace: pdb.Namespace, Name: pdb.Name}
+		problems := getPDBProblems(pdb, allowedSince[pdb.UID], covered[nn], overlaps[nn], o.blockedFor, now)
+		if len(problems) == 0 {
+			continue
+		}
+
+		age := getAge(pdb.CreationTimestamp)
+		row := []string{pdb.Name, formatIntOrString(pdb.Spec.MinAvailable), formatIntOrString(pdb.Spec.MaxUnavailable), strconv.Itoa(int(pdb.Status.DisruptionsAllowed)), strconv.Itoa(covered[nn]), strings.Join(problems, ","), age}
+		if o.allNamespaces {
+			row = append([]string{pdb.Namespace}, row...)
+		}
+		uids = append(uids, pdb.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "MIN AVAILABLE", "MAX UNAVAILABLE", "ALLOWED DISRUPTIONS", "PODS", "PROBLEMS", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace, "ALLOWED DISRUPTIONS", "PODS"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPDBCoverage returns the number of Pods covered by each PodDisruptionBudget
+// and, for each of them, the other PodDisruptionBudgets covering the same Pods.
+func getPDBCoverage(pods []corev1.Pod, pdbs []policyv1beta1.PodDisruptionBudget) (map[types.NamespacedName]int, map[types.NamespacedName][]string) {
+	covered := make(map[types.NamespacedName]int)
+	overlapping := make(map[types.NamespacedName]map[string]bool)
+
+	for _, pod := range pods {
+		matching := getMatchingPDBs(pod, pdbs)
+		for _, pdb := range matching {
+			nn := types.NamespacedName{Namespace: pdb.Namespace, Name: pdb.Name}
+			covered[nn]++
+
+			for _, other := range matching {
+				if other.Name == pdb.Name {
+					continue
+				}
+				if overlapping[nn] == nil {
+					overlapping[nn] = make(map[string]bool)
+				}
+				overlapping[nn][other.Name] = true
+			}
+		}
+	}
+
+	overlaps := make(map[types.NamespacedName][]string)
+	for nn, names := range overlapping {
+		for name := range names {
+			overlaps[nn] = append(overlaps[nn], name)
+		}
+		sort.Strings(overlaps[nn])
+	}
+
+	return covered, overlaps
+}
+
+// getPDBProblems returns the reasons a PodDisruptionBudget blocks evictions or
+// is misconfigured. The allowedSince is the last transition of its DisruptionAllowed
+// condition, if any, the pods are the number of Pods it covers and the overlaps
+// the other PodDisruptionBudgets covering some of them.
+func getPDBProblems(pdb policyv1beta1.PodDisruptionBudget, allowedSince metav1.Time, pods int, overlaps []string, blockedFor time.Duration, now time.Time) []string {
+	if pods == 0 {
+		return []string{"NoMatchingPods"}
+	}
+
+	var problems []string
+
+	if pdb.Status.DisruptionsAllowed == 0 {
+		since, approximate := allowedSince, false
+		if since.IsZero() {
+			since, approximate = getPDBStatusTransitionTime(pdb), true
+		}
+
+		blocked := now.Sub(since.Time)
+		if blocked > blockedFor {
+			age := duration.HumanDuration(blocked)
+			if approximate {
+				age = "~" + age
+			}
+			problems = append(problems, fmt.Sprintf("NoDisruptionsAllowed(for %s)", age))
+		}
+	}
+
+	if minAvailable := pdb.Spec.MinAvailable; minAvailable != nil {
+		switch {
+		case minAvailable.Type == intstr.String && minAvailable.StrVal == "100%":
+			problems = append(problems, "MinAvailableEqualsReplicas(100%)")
+		case minAvailable.Type == intstr.Int && pdb.Status.ExpectedPods > 0 && minAvailable.IntVal >= pdb.Status.ExpectedPods:
+			problems = append(problems, fmt.Sprintf("MinAvailableEqualsReplicas(%d)", pdb.Status.ExpectedPods))
+		}
+	}
+
+	if len(overlaps) > 0 {
+		problems = append(problems, fmt.Sprintf("OverlapsWith(%s)", strings.Join(overlaps, ",")))
+	}
+
+	return problems
+}
+
+// getPDBStatusTransitionTime approximates since when the PodDisruptionBudget
+// allows its current number of disruptions, using the last update of
+// status.disruptionsAllowed recorded in its managed fields. It is only used when
+// the DisruptionAllowed condition is missing.
+func getPDBStatusTransitionTime(pdb policyv1beta1.PodDisruptionBudget) metav1.Time {
+	latest := pdb.CreationTimestamp
+	for _, entry := range pdb.ManagedFields {
+		if entry.Time == nil || entry.FieldsV1 == nil || !bytes.Contains(entry.FieldsV1.Raw, []byte(`"f:disruptionsAllowed"`)) {
+			continue
+		}
+		if latest.Before(entry.Time) {
+			latest = *entry.Time
+		}
+	}
+	return latest
+}
+
+// getDisruptionAllowedTransitionTime returns the last transition time of the
+// DisruptionAllowed condition of the PodDisruptionBudget, or a zero time when
+// the cluster does not set it.
+func getDisruptionAllowedTransitionTime(pdb unstructured.Unstructured) metav1.Time {
+	conditions, _, _ := unstructured.NestedSlice(pdb.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "DisruptionAllowed" {
+			continue
+		}
+		lastTransitionTime, _ := condition["lastTransitionTime"].(string)
+		if ts, err := time.Parse(time.RFC3339, lastTransitionTime); err == nil {
+			return metav1.NewTime(ts)
+		}
+	}
+	return metav1.Time{}
+}
+
+// formatIntOrString formats an optional int or percentage field.
+func formatIntOrString(value *intstr.IntOrString) string {
+	if value == nil {
+		return "N/A"
+	}
+	return value.String()
+}
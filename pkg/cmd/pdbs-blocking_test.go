@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPDBCoverage(t *testing.T) {
+	t.Parallel()
+
+	pdbs := []policyv1beta1.PodDisruptionBudget{
+		newTestPDB("web", map[string]string{"app": "web"}, 0),
+		newTestPDB("frontend", map[string]string{"tier": "frontend"}, 0),
+		newTestPDB("db", map[string]string{"app": "db"}, 0),
+		newTestPDB("empty", map[string]string{}, 0),
+	}
+	pods := []corev1.Pod{
+		newTestPod(testNamespace, "web-1", withLabels(map[string]string{"app": "web", "tier": "frontend"})),
+		newTestPod(testNamespace, "web-2", withLabels(map[string]string{"app": "web"})),
+		newTestPod(testNamespace, "db-1", withLabels(map[string]string{"app": "db"})),
+	}
+
+	covered, overlaps := getPDBCoverage(pods, pdbs)
+
+	key := func(name string) types.NamespacedName {
+		return types.NamespacedName{Namespace: "default", Name: name}
+	}
+	assert.Equal(t, map[types.NamespacedName]int{key("web"): 2, key("frontend"): 1, key("db"): 1}, covered)
+	assert.Equal(t, map[types.NamespacedName][]string{key("web"): {"frontend"}, key("frontend"): {"web"}}, overlaps)
+}
+
+func TestGetPDBProblems(t *testing.T) {
+	now := time.Now()
+
+	minAvailable := func(value intstr.IntOrString) policyv1beta1.PodDisruptionBudget {
+		return policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: now.Add(-3 * time.Hour)}},
+			Spec:       policyv1beta1.PodDisruptionBudgetSpec{MinAvailable: &value},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1, ExpectedPods: 3},
+		}
+	}
+
+	blockedRecently := minAvailable(intstr.FromInt(2))
+	blockedRecently.Status.DisruptionsAllowed = 0
+	blockedRecently.ManagedFields = []metav1.ManagedFieldsEntry{{
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      &metav1.Time{Time: now.Add(-10 * time.Minute)},
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:disruptionsAllowed":{}}}`)},
+	}}
+
+	blockedLongAgo := minAvailable(intstr.FromInt(3))
+	blockedLongAgo.Status.DisruptionsAllowed = 0
+
+	blockedSinceCondition := minAvailable(intstr.FromInt(2))
+	blockedSinceCondition.Status.DisruptionsAllowed = 0
+
+	tests := []struct {
+		name         string
+		pdb          policyv1beta1.PodDisruptionBudget
+		allowedSince metav1.Time
+		pods         int
+		overlaps     []string
+		want         []string
+	}{
+		{
+			name: "PodDisruptionBudget allowing disruptions is expected to be healthy",
+			pdb:  minAvailable(intstr.FromInt(2)),
+			pods: 3,
+			want: nil,
+		},
+		{
+			name: "PodDisruptionBudget selecting no Pods is expected to be reported",
+			pdb:  minAvailable(intstr.FromInt(2)),
+			pods: 0,
+			want: []string{"NoMatchingPods"},
+		},
+		{
+			name: "PodDisruptionBudget blocked for less than the threshold is expected to be healthy",
+			pdb:  blockedRecently,
+			pods: 3,
+			want: nil,
+		},
+		{
+			name: "PodDisruptionBudget requiring every replica is expected to be reported",
+			pdb:  blockedLongAgo,
+			pods: 3,
+			want: []string{"NoDisruptionsAllowed(for ~3h)", "MinAvailableEqualsReplicas(3)"},
+		},
+		{
+			name:         "PodDisruptionBudget blocked since its DisruptionAllowed condition is expected to be reported exactly",
+			pdb:          blockedSinceCondition,
+			allowedSince: metav1.NewTime(now.Add(-4 * time.Hour)),
+			pods:         3,
+			want:         []string{"NoDisruptionsAllowed(for 4h)"},
+		},
+		{
+			name:     "PodDisruptionBudget at 100% overlapping another one is expected to be reported",
+			pdb:      minAvailable(intstr.FromString("100%")),
+			pods:     3,
+			overlaps: []string{"other"},
+			want:     []string{"MinAvailableEqualsReplicas(100%)", "OverlapsWith(other)"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getPDBProblems(tt.pdb, tt.allowedSince, tt.pods, tt.overlaps, time.Hour, now))
+		})
+	}
+}
+
+func TestGetDisruptionAllowedTransitionTime(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2020, time.December, 1, 12, 0, 0, 0, time.UTC)
+	pdb := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Other", "lastTransitionTime": "2019-01-01T00:00:00Z"},
+				map[string]interface{}{"type": "DisruptionAllowed", "status": "False", "lastTransitionTime": ts.Format(time.RFC3339)},
+			},
+		},
+	}}
+
+	got := getDisruptionAllowedTransitionTime(pdb)
+	assert.True(t, ts.Equal(got.Time))
+	assert.True(t, getDisruptionAllowedTransitionTime(unstructured.Unstructured{Object: map[string]interface{}{}}).Time.IsZero())
+}
+
+func TestBlockingPDBsRun(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	pdb := newTestPDB("web", labels, 0)
+	pdb.UID = "web-uid"
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pdb)
+	assert.NoError(t, err)
+
+	u := unstructured.Unstructured{Object: obj}
+	u.SetAPIVersion("policy/v1beta1")
+	u.SetKind("PodDisruptionBudget")
+	condition := map[string]interface{}{"type": "DisruptionAllowed", "status": "False", "lastTransitionTime": time.Now().Add(-4 * time.Hour).UTC().Format(time.RFC3339)}
+	assert.NoError(t, unstructured.SetNestedSlice(u.Object, []interface{}{condition}, "status", "conditions"))
+
+	pod := newTestPod(testNamespace, "web-1", withLabels(labels))
+	options, _, out, _ := newFakeJanitorOptions(fake.NewSimpleClientset(&pod))
+	options.dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &u)
+	o := newBlockingPDBsOptions(options)
+	o.blockedFor = time.Hour
+
+	err = o.Run(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "NoDisruptionsAllowed(for 4h)")
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newPDBsCommand provides the base command when called without any subcommands.
+func newPDBsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "pdbs",
+		Short:        "Find PodDisruptionBudgets in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newBlockingPDBsCommand(factory, options))
+
+	return cmd
+}
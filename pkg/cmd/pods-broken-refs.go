@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// BrokenRefsPodsOptions embeds JanitorOptions struct.
+type BrokenRefsPodsOptions struct {
+	JanitorOptions
+}
+
+// podRefIndex holds the objects and keys that Pods can reference.
+type podRefIndex struct {
+	configMaps map[types.NamespacedName]map[string]bool
+	secrets    map[types.NamespacedName]map[string]bool
+	pvcs       map[types.NamespacedName]bool
+}
+
+// brokenRef describes a reference of a Pod to a missing object or key.
+type brokenRef struct {
+	kind    string
+	name    string
+	key     string
+	usedBy  string
+	problem string
+}
+
+// newBrokenRefsPodsOptions creates an instance of BrokenRefsPodsOptions.
+func newBrokenRefsPodsOptions(options JanitorOptions) *BrokenRefsPodsOptions {
+	return &BrokenRefsPodsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newBrokenRefsPodsCommand returns a cobra command wrapping BrokenRefsPodsOptions.
+func newBrokenRefsPodsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newBrokenRefsPodsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "broken-refs",
+		Short:        "List Pods referencing PersistentVolumeClaims, ConfigMaps, Secrets or keys that do not exist",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run lists Pods with references to missing objects.
+func (o *BrokenRefsPodsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	pods, err := client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	idx, err := getPodRefIndex(ctx, client, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, pod := range pods.Items {
+		age := getAge(pod.CreationTimestamp)
+		podStatus := getPodStatus(pod)
+		for _, ref := range getPodBrokenRefs(pod, idx) {
+			reference := ref.kind + "/" + ref.name
+			if ref.key != "" {
+				reference += "[" + ref.key + "]"
+			}
+
+			row := []string{pod.Name, podStatus, reference, ref.usedBy, ref.problem, age}
+			if o.allNamespaces {
+				row = append([]string{pod.Namespace}, row...)
+			}
+			uids = append(uids, pod.UID)
+			matrix = append(matrix, row)
+		}
+	}
+
+	headers := []string{"NAME", "STATUS", "REFERENCE", "USED BY", "PROBLEM", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPodRefIndex collects the ConfigMaps, Secrets and PersistentVolumeClaims of the namespace.
+func getPodRefIndex(ctx context.Context, client kubernetes.Interface, namespace string) (podRefIndex, error) {
+	idx := podRefIndex{
+		configMaps: make(map[types.NamespacedName]map[string]bool),
+		secrets:    make(map[types.NamespacedName]map[string]bool),
+		pvcs:       make(map[types.NamespacedName]bool),
+	}
+
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return idx, err
+	}
+	for _, cm := range configMaps.Items {
+		keys := make(map[string]bool)
+		for key := range cm.Data {
+			keys[key] = true
+		}
+		for key := range cm.BinaryData {
+			keys[key] = true
+		}
+		idx.configMaps[types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}] = keys
+	}
+
+	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return idx, err
+	}
+	for _, secret := range secrets.Items {
+		keys := make(map[string]bool)
+		for key := range secret.Data {
+			keys[key] = true
+		}
+		idx.secrets[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}] = keys
+	}
+
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return idx, err
+	}
+	for _, pvc := range pvcs.Items {
+		idx.pvcs[types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}] = true
+	}
+
+	return idx, nil
+}
+
+// getPodBrokenRefs returns the references of the Pod to PersistentVolumeClaims,
+// ConfigMaps, Secrets and keys that do not exist. Optional references are ignored.
+func getPodBrokenRefs(pod corev1.Pod, idx podRefIndex) []brokenRef {
+	var refs []brokenRef
+	seen := make(map[brokenRef]bool)
+
+	add := func(ref brokenRef) {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	check := func(kind, name, key, usedBy string, optional *bool) {
+		if optional != nil && *optional {
+			return
+		}
+
+		switch problem := idx.getProblem(pod.Namespace, kind, name, key); problem {
+		case "NotFound":
+			add(brokenRef{kind, name, "", usedBy, problem})
+		case "KeyNotFound":
+			add(brokenRef{kind, name, key, usedBy, problem})
+		}
+	}
+
+	walkPodRefs(pod, check)
+
+	return refs
+}
+
+// getProblem returns NotFound when the referenced object does not exist,
+// KeyNotFound when it lacks the key, or an empty string.
+func (idx podRefIndex) getProblem(namespace, kind, name, key string) string {
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+
+	var keys map[string]bool
+	var found bool
+	switch kind {
+	case "configmap":
+		keys, found = idx.configMaps[nn]
+	case "secret":
+		keys, found = idx.secrets[nn]
+	case "persistentvolumeclaim":
+		found = idx.pvcs[nn]
+	}
+
+	switch {
+	case !found:
+		return "NotFound"
+	case key != "" && !keys[key]:
+		return "KeyNotFound"
+	}
+	return ""
+}
+
+// walkPodRefs calls check for every reference of the Pod to a PersistentVolumeClaim,
+// ConfigMap, Secret or one of their keys, with the part of the Pod using it.
+func walkPodRefs(pod corev1.Pod, check func(kind, name, key, usedBy string, optional *bool)) {
+	for _, volume := range pod.Spec.Volumes {
+		usedBy := "volume/" + volume.Name
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			check("persistentvolumeclaim", volume.PersistentVolumeClaim.ClaimName, "", usedBy, nil)
+		case volume.ConfigMap != nil:
+			checkKeyToPaths(check, "configmap", volume.ConfigMap.Name, volume.ConfigMap.Items, usedBy, volume.ConfigMap.Optional)
+		case volume.Secret != nil:
+			checkKeyToPaths(check, "secret", volume.Secret.SecretName, volume.Secret.Items, usedBy, volume.Secret.Optional)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					checkKeyToPaths(check, "configmap", source.ConfigMap.Name, source.ConfigMap.Items, usedBy, source.ConfigMap.Optional)
+				}
+				if source.Secret != nil {
+					checkKeyToPaths(check, "secret", source.Secret.Name, source.Secret.Items, usedBy, source.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			usedBy := "container/" + container.Name + " envFrom"
+			if envFrom.ConfigMapRef != nil {
+				check("configmap", envFrom.ConfigMapRef.Name, "", usedBy, envFrom.ConfigMapRef.Optional)
+			}
+			if envFrom.SecretRef != nil {
+				check("secret", envFrom.SecretRef.Name, "", usedBy, envFrom.SecretRef.Optional)
+			}
+		}
+
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+
+			usedBy := "container/" + container.Name + " env/" + env.Name
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				check("configmap", ref.Name, ref.Key, usedBy, ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				check("secret", ref.Name, ref.Key, usedBy, ref.Optional)
+			}
+		}
+	}
+
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		check("secret", ref.Name, "", "imagePullSecrets", nil)
+	}
+}
+
+// checkKeyToPaths checks a ConfigMap or Secret projected into a volume,
+// including every key selected through items.
+func checkKeyToPaths(check func(kind, name, key, usedBy string, optional *bool), kind, name string, items []corev1.KeyToPath, usedBy string, optional *bool) {
+	if len(items) == 0 {
+		check(kind, name, "", usedBy, optional)
+		return
+	}
+
+	for _, item := range items {
+		check(kind, name, item.Key, usedBy, optional)
+	}
+}
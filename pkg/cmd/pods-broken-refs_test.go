@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetPodBrokenRefs(t *testing.T) {
+	optional := true
+
+	idx := podRefIndex{
+		configMaps: map[types.NamespacedName]map[string]bool{
+			{Namespace: "default", Name: "app-config"}: {"LOG_LEVEL": true},
+		},
+		secrets: map[types.NamespacedName]map[string]bool{
+			{Namespace: "default", Name: "app-secret"}: {"password": true},
+		},
+		pvcs: map[types.NamespacedName]bool{
+			{Namespace: "default", Name: "data"}: true,
+		},
+	}
+
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want []brokenRef
+	}{
+		{
+			name: "Pod referencing existing objects and keys is expected to have no broken references",
+			pod: newTestPod(testNamespace, "app", withSpec(corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+				},
+				Containers: []corev1.Container{{
+					Name: "app",
+					Env: []corev1.EnvVar{{
+						Name: "LOG_LEVEL",
+						ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+							Key:                  "LOG_LEVEL",
+						}},
+					}},
+				}},
+			})),
+			want: nil,
+		},
+		{
+			name: "Pod referencing a missing PersistentVolumeClaim and pull Secret is expected to be reported",
+			pod: newTestPod(testNamespace, "app", withSpec(corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "cache", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cache"}}},
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+			})),
+			want: []brokenRef{
+				{"persistentvolumeclaim", "cache", "", "volume/cache", "NotFound"},
+				{"secret", "registry", "", "imagePullSecrets", "NotFound"},
+			},
+		},
+		{
+			name: "Pod referencing a missing Secret key is expected to be reported",
+			pod: newTestPod(testNamespace, "app", withSpec(corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "app",
+					Env: []corev1.EnvVar{{
+						Name: "TOKEN",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "app-secret"},
+							Key:                  "token",
+						}},
+					}},
+				}},
+			})),
+			want: []brokenRef{
+				{"secret", "app-secret", "token", "container/app env/TOKEN", "KeyNotFound"},
+			},
+		},
+		{
+			name: "Pod referencing a missing projected ConfigMap is expected to be reported once",
+			pod: newTestPod(testNamespace, "app", withSpec(corev1.PodSpec{
+				Volumes: []corev1.Volume{{
+					Name: "config",
+					VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{
+							ConfigMap: &corev1.ConfigMapProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "extra"},
+								Items:                []corev1.KeyToPath{{Key: "a", Path: "a"}, {Key: "b", Path: "b"}},
+							},
+						}},
+					}},
+				}},
+			})),
+			want: []brokenRef{
+				{"configmap", "extra", "", "volume/config", "NotFound"},
+			},
+		},
+		{
+			name: "Pod with optional references is expected to have no broken references",
+			pod: newTestPod(testNamespace, "app", withSpec(corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "app",
+					EnvFrom: []corev1.EnvFromSource{{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+							Optional:             &optional,
+						},
+					}},
+				}},
+			})),
+			want: nil,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getPodBrokenRefs(tc.pod, idx)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
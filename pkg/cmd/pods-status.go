@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"strconv"
@@ -89,9 +88,9 @@ func (o *StatusPodsOptions) Run(ctx context.Context, noHeader bool) error {
 
 	headers := []string{"STATUS", "COUNT"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
-
-	return nil
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printResults(headers, matrix, nil, o.namespace, "COUNT")
 }
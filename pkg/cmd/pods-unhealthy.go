@@ -1,19 +1,77 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+const (
+	// maxGroupExamples is the number of example Pods shown for each group.
+	maxGroupExamples = 3
+	// overRepresentedLift is how many times larger than expected the share of
+	// unhealthy Pods of a group must be to consider it over-represented.
+	overRepresentedLift = 2.0
+	// overRepresentedShare is the share of unhealthy Pods above which a group
+	// larger than expected is over-represented, since a group holding more
+	// than half of all Pods can never reach overRepresentedLift.
+	overRepresentedShare = 0.5
+	// overRepresentedMinPods is the minimum number of unhealthy Pods in a group
+	// to consider it over-represented.
+	overRepresentedMinPods = 3
+	// defaultLogLines is the number of log lines fetched when --logs is given without a value.
+	defaultLogLines = 20
+	// maxConcurrentLogs is the number of container logs fetched at the same time.
+	maxConcurrentLogs = 5
+)
+
+// podGroupKeys maps the supported --group-by values to their column header.
+var podGroupKeys = map[string]string{
+	"node":      "NODE",
+	"owner":     "OWNER",
+	"status":    "STATUS",
+	"image":     "IMAGE",
+	"namespace": "NAMESPACE",
+}
+
 // UnhealthyPodsOptions embeds JanitorOptions struct.
 type UnhealthyPodsOptions struct {
 	JanitorOptions
+	groupBy string
+	logs    int
+	hints   bool
+}
+
+// containerLogs holds the last termination of a crashing container and the
+// last lines of the logs of that instance.
+type containerLogs struct {
+	Container string   `json:"container"`
+	Reason    string   `json:"reason"`
+	ExitCode  int32    `json:"exitCode"`
+	Lines     []string `json:"lines"`
+	Error     string   `json:"error,omitempty"`
+	previous  bool
+}
+
+// podGroup aggregates the unhealthy Pods sharing the same node, owner, status, image or namespace.
+type podGroup struct {
+	key             string
+	unhealthy       int
+	total           int
+	examples        []string
+	share           float64
+	overRepresented bool
 }
 
 // newUnhealthyPodsOptions creates an instance of UnhealthyPodsOptions.
@@ -47,12 +105,26 @@ func newUnhealthyPodsCommand(factory cmdutil.Factory, options JanitorOptions) *c
 	}
 
 	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&o.groupBy, "group-by", "", "Aggregate the unhealthy Pods by node, owner, status, image or namespace.")
+	cmd.Flags().IntVar(&o.logs, "logs", 0, "Show the last N log lines of the previous instance of the containers of crashing Pods (use --logs=N).")
+	cmd.Flags().Lookup("logs").NoOptDefVal = strconv.Itoa(defaultLogLines)
+	cmd.Flags().BoolVar(&o.hints, "hints", false, "Show an explanation and the next commands to run for the reasons of every unhealthy Pod.")
 
 	return cmd
 }
 
 // Run finds pods that are unhealthy.
 func (o *UnhealthyPodsOptions) Run(ctx context.Context, noHeader bool) error {
+	if _, ok := podGroupKeys[o.groupBy]; o.groupBy != "" && !ok {
+		return fmt.Errorf("invalid --group-by %q: must be one of node, owner, status, image or namespace", o.groupBy)
+	}
+	if o.logs < 0 {
+		return fmt.Errorf("invalid --logs %d: must be a positive number of lines", o.logs)
+	}
+	if o.groupBy != "" && (o.logs > 0 || o.hints) {
+		return fmt.Errorf("--logs and --hints cannot be used with --group-by")
+	}
+
 	client, err := o.GetClient()
 	if err != nil {
 		return err
@@ -63,7 +135,32 @@ func (o *UnhealthyPodsOptions) Run(ctx context.Context, noHeader bool) error {
 		return err
 	}
 
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+
+	if o.groupBy != "" {
+		var matrix [][]string
+		for _, group := range groupUnhealthyPods(pods.Items, o.groupBy, o.allNamespaces) {
+			overRepresented := ""
+			if group.overRepresented {
+				overRepresented = "yes"
+			}
+
+			share := strconv.FormatFloat(group.share*100, 'f', 0, 64) + "%"
+			row := []string{group.key, strconv.Itoa(group.unhealthy), strconv.Itoa(group.total), share, overRepresented, strings.Join(group.examples, ",")}
+			matrix = append(matrix, row)
+		}
+
+		headers := []string{podGroupKeys[o.groupBy], "UNHEALTHY", "PODS", "SHARE", "OVER-REPRESENTED", "EXAMPLES"}
+
+		return p.printClusterResults(headers, matrix, nil, "UNHEALTHY", "PODS")
+	}
+
 	var matrix [][]string
+	var uids []types.UID
+	var unhealthy []corev1.Pod
 
 	for _, pod := range pods.Items {
 		if !isPodHealthy(pod) {
@@ -73,15 +170,242 @@ func (o *UnhealthyPodsOptions) Run(ctx context.Context, noHeader bool) error {
 			if o.allNamespaces {
 				row = append([]string{pod.Namespace}, row...)
 			}
+			uids = append(uids, pod.UID)
 			matrix = append(matrix, row)
+			unhealthy = append(unhealthy, pod)
 		}
 	}
 
 	headers := []string{"NAME", "STATUS", "AGE"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
+	var details []resultDetails
+
+	if o.hints {
+		catalog, err := o.getHintCatalog()
+		if err != nil {
+			return err
+		}
+
+		events, err := getEvents(ctx, client, o.namespace, "Pod", corev1.EventTypeWarning)
+		if err != nil {
+			return err
+		}
+
+		hints := resultDetails{key: "hints"}
+		for _, pod := range unhealthy {
+			podHints := getPodHints(pod, events[pod.UID], catalog)
+			hints.lines = append(hints.lines, getPodHintsLines(podHints))
+			hints.values = append(hints.values, podHints)
+		}
+		details = append(details, hints)
+	}
+
+	if o.logs > 0 {
+		logs := resultDetails{key: "logs"}
+		for _, podLogs := range getCrashingPodsLogs(ctx, client, unhealthy, int64(o.logs)) {
+			var lines []string
+			for _, l := range podLogs {
+				lines = append(lines, getContainerLogsLines(l)...)
+			}
+			logs.lines = append(logs.lines, lines)
+			logs.values = append(logs.values, podLogs)
+		}
+		details = append(details, logs)
+	}
+
+	if len(details) == 0 {
+		return p.printResults(headers, matrix, uids, o.namespace)
+	}
+	return p.printResultsWithDetails(headers, matrix, uids, o.namespace, details)
+}
+
+// getCrashedContainers returns the last termination of the containers of a Pod
+// that getPodStatus reports as CrashLoopBackOff or Error, without their logs.
+// Only the containers waiting in CrashLoopBackOff or terminated with a non-zero
+// exit code are returned, not the ones that restarted once and are running.
+func getCrashedContainers(pod corev1.Pod) []containerLogs {
+	switch getPodStatus(pod) {
+	case "CrashLoopBackOff", "Error":
+	default:
+		return []containerLogs{}
+	}
+
+	crashed := []containerLogs{}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" && status.LastTerminationState.Terminated != nil:
+			last := status.LastTerminationState.Terminated
+			crashed = append(crashed, containerLogs{Container: status.Name, Reason: last.Reason, ExitCode: last.ExitCode, Lines: []string{}, previous: true})
+		case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+			// A container that was never restarted has no previous instance,
+			// the logs of its terminated instance are fetched instead.
+			current := status.State.Terminated
+			crashed = append(crashed, containerLogs{Container: status.Name, Reason: current.Reason, ExitCode: current.ExitCode, Lines: []string{}})
+		}
+	}
+	return crashed
+}
+
+// getCrashingPodsLogs fetches the last lines of the logs of the crashed containers
+// of every Pod, at most maxConcurrentLogs at a time. The logs are returned in the
+// order of the Pods.
+func getCrashingPodsLogs(ctx context.Context, client kubernetes.Interface, pods []corev1.Pod, tailLines int64) [][]containerLogs {
+	logs := make([][]containerLogs, len(pods))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLogs)
+
+	for i, pod := range pods {
+		logs[i] = getCrashedContainers(pod)
+		for j := range logs[i] {
+			wg.Add(1)
+			go func(pod corev1.Pod, l *containerLogs) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				options := &corev1.PodLogOptions{Container: l.Container, Previous: l.previous, TailLines: &tailLines}
+				data, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(ctx)
+				if err != nil {
+					l.Error = err.Error()
+					return
+				}
+				l.Lines = splitLogLines(string(data))
+			}(pod, &logs[i][j])
+		}
+	}
+
+	wg.Wait()
+	return logs
+}
+
+// splitLogLines splits logs into lines, without the trailing newline.
+func splitLogLines(data string) []string {
+	data = strings.TrimRight(data, "\n")
+	if data == "" {
+		return []string{}
+	}
+	return strings.Split(data, "\n")
+}
+
+// getContainerLogsLines returns the lines shown under a Pod in the table formats.
+func getContainerLogsLines(l containerLogs) []string {
+	instance := "previous"
+	if !l.previous {
+		instance = "terminated"
+	}
+
+	reason := l.Reason
+	if reason == "" {
+		reason = "<none>"
+	}
 
+	lines := []string{fmt.Sprintf("%s: %s, exit code %d, logs of the %s instance:", l.Container, reason, l.ExitCode, instance)}
+	switch {
+	case l.Error != "":
+		lines = append(lines, "  "+l.Error)
+	case len(l.Lines) == 0:
+		lines = append(lines, "  <none>")
+	}
+	for _, line := range l.Lines {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+// getPodGroupKeys returns the groups a Pod belongs to. A Pod running several
+// images belongs to one group per image.
+func getPodGroupKeys(pod corev1.Pod, groupBy string, allNamespaces bool) []string {
+	switch groupBy {
+	case "node":
+		if pod.Spec.NodeName == "" {
+			return []string{"<none>"}
+		}
+		return []string{pod.Spec.NodeName}
+	case "owner":
+		key := "<none>"
+		if owner := metav1.GetControllerOf(&pod); owner != nil {
+			key = owner.Kind + "/" + owner.Name
+		}
+		if allNamespaces {
+			key = pod.Namespace + "/" + key
+		}
+		return []string{key}
+	case "status":
+		return []string{getPodStatus(pod)}
+	case "image":
+		var images []string
+		seen := make(map[string]bool)
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			if !seen[container.Image] {
+				seen[container.Image] = true
+				images = append(images, container.Image)
+			}
+		}
+		return images
+	case "namespace":
+		return []string{pod.Namespace}
+	}
 	return nil
 }
+
+// groupUnhealthyPods aggregates the unhealthy Pods by the given key. A group is
+// over-represented when its share of unhealthy Pods is much larger than its
+// share of all Pods, or holds most unhealthy Pods and is still larger than its
+// share of all Pods, which points at a bad node or a bad rollout.
+func groupUnhealthyPods(pods []corev1.Pod, groupBy string, allNamespaces bool) []podGroup {
+	groups := make(map[string]*podGroup)
+	totalUnhealthy := 0
+
+	for _, pod := range pods {
+		healthy := isPodHealthy(pod)
+		if !healthy {
+			totalUnhealthy++
+		}
+
+		for _, key := range getPodGroupKeys(pod, groupBy, allNamespaces) {
+			group, ok := groups[key]
+			if !ok {
+				group = &podGroup{key: key}
+				groups[key] = group
+			}
+
+			group.total++
+			if !healthy {
+				group.unhealthy++
+				if len(group.examples) < maxGroupExamples {
+					name := pod.Name
+					if allNamespaces {
+						name = pod.Namespace + "/" + name
+					}
+					group.examples = append(group.examples, name)
+				}
+			}
+		}
+	}
+
+	var result []podGroup
+	for _, group := range groups {
+		if group.unhealthy == 0 {
+			continue
+		}
+
+		group.share = float64(group.unhealthy) / float64(totalUnhealthy)
+		expected := float64(group.total) / float64(len(pods))
+		lifted := group.share >= expected*overRepresentedLift
+		dominant := group.share >= overRepresentedShare && group.share > expected
+		group.overRepresented = len(groups) > 1 && group.unhealthy >= overRepresentedMinPods && (lifted || dominant)
+		result = append(result, *group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].unhealthy != result[j].unhealthy {
+			return result[i].unhealthy > result[j].unhealthy
+		}
+		return result[i].key < result[j].key
+	})
+
+	return result
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGroupUnhealthyPods(t *testing.T) {
+	// newPods returns total Pods on the node, of which the first unhealthy are Failed.
+	newPods := func(first int, node string, total, unhealthy int) []corev1.Pod {
+		var pods []corev1.Pod
+		for i := 0; i < total; i++ {
+			phase := corev1.PodRunning
+			if i < unhealthy {
+				phase = corev1.PodFailed
+			}
+			pods = append(pods, newTestPod(testNamespace, fmt.Sprintf("pod-%d", first+i), onNode(node), inPhase(phase)))
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want []podGroup
+	}{
+		{
+			name: "node with twice its share of unhealthy Pods is expected to be over-represented",
+			pods: append(append(append(append(newPods(0, "node-a", 4, 4), newPods(4, "node-b", 4, 0)...), newPods(8, "node-c", 4, 0)...), newPods(12, "node-d", 4, 0)...),
+				newTestPod(testNamespace, "pod-16", onNode("node-b"), inPhase(corev1.PodFailed))),
+			want: []podGroup{
+				{key: "node-a", unhealthy: 4, total: 4, examples: []string{"pod-0", "pod-1", "pod-2"}, share: 0.8, overRepresented: true},
+				{key: "node-b", unhealthy: 1, total: 5, examples: []string{"pod-16"}, share: 0.2, overRepresented: false},
+			},
+		},
+		{
+			name: "node holding most unhealthy Pods of two even nodes is expected to be over-represented",
+			pods: append(newPods(0, "node-a", 5, 4), newPods(5, "node-b", 5, 1)...),
+			want: []podGroup{
+				{key: "node-a", unhealthy: 4, total: 5, examples: []string{"pod-0", "pod-1", "pod-2"}, share: 0.8, overRepresented: true},
+				{key: "node-b", unhealthy: 1, total: 5, examples: []string{"pod-5"}, share: 0.2, overRepresented: false},
+			},
+		},
+		{
+			name: "node holding its share of unhealthy Pods is not expected to be over-represented",
+			pods: append(newPods(0, "node-a", 8, 4), newPods(8, "node-b", 2, 1)...),
+			want: []podGroup{
+				{key: "node-a", unhealthy: 4, total: 8, examples: []string{"pod-0", "pod-1", "pod-2"}, share: 0.8, overRepresented: false},
+				{key: "node-b", unhealthy: 1, total: 2, examples: []string{"pod-8"}, share: 0.2, overRepresented: false},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := groupUnhealthyPods(tc.pods, "node", false)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetPodGroupKeys(t *testing.T) {
+	controller := true
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "production",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d4f", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Image: "busybox"}},
+			Containers:     []corev1.Container{{Image: "nginx:1.19"}, {Image: "busybox"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodFailed},
+	}
+
+	tests := []struct {
+		name          string
+		groupBy       string
+		allNamespaces bool
+		want          []string
+	}{
+		{name: "unscheduled Pod is expected to have no node", groupBy: "node", want: []string{"<none>"}},
+		{name: "owner is expected to be the controller", groupBy: "owner", want: []string{"ReplicaSet/web-5d4f"}},
+		{name: "owner is expected to be namespaced across namespaces", groupBy: "owner", allNamespaces: true, want: []string{"production/ReplicaSet/web-5d4f"}},
+		{name: "images are expected to be deduplicated", groupBy: "image", want: []string{"busybox", "nginx:1.19"}},
+		{name: "status is expected to come from getPodStatus", groupBy: "status", want: []string{"Failed"}},
+		{name: "namespace is expected to be the Pod namespace", groupBy: "namespace", want: []string{"production"}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getPodGroupKeys(pod, tc.groupBy, tc.allNamespaces)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetCrashedContainers(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want []containerLogs
+	}{
+		{
+			name: "crash looping Pod is expected to return the last termination of its restarted containers",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name:                 "app",
+							State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+							LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+						},
+						{
+							Name:                 "sidecar",
+							State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+							LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+						},
+					},
+				},
+			},
+			want: []containerLogs{{Container: "app", Reason: "OOMKilled", ExitCode: 137, Lines: []string{}, previous: true}},
+		},
+		{
+			name: "failed Pod never restarted is expected to return its terminated container",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodFailed,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "app",
+						State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+					}},
+				},
+			},
+			want: []containerLogs{{Container: "app", Reason: "Error", ExitCode: 1, Lines: []string{}}},
+		},
+		{
+			name: "Pod waiting for its image is expected to have no crashed container",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "app",
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+					}},
+				},
+			},
+			want: []containerLogs{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getCrashedContainers(tt.pod))
+		})
+	}
+}
+
+func TestUnhealthyPodsRunInvalidFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy string
+		logs    int
+		hints   bool
+	}{
+		{name: "unknown --group-by is expected to be rejected", groupBy: "zone"},
+		{name: "negative --logs is expected to be rejected", logs: -1},
+		{name: "--logs with --group-by is expected to be rejected", groupBy: "node", logs: defaultLogLines},
+		{name: "--hints with --group-by is expected to be rejected", groupBy: "node", hints: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options, _, out, _ := newFakeJanitorOptions(fake.NewSimpleClientset())
+			o := newUnhealthyPodsOptions(options)
+			o.groupBy, o.logs, o.hints = tt.groupBy, tt.logs, tt.hints
+
+			assert.Error(t, o.Run(context.Background(), false))
+			assert.Empty(t, out.String())
+		})
+	}
+}
+
+func TestGetCrashingPodsLogs(t *testing.T) {
+	crashing := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "crashing"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "app",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 2}},
+			}},
+		},
+	}
+	pending := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pending"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+
+	client := fake.NewSimpleClientset(&crashing, &pending)
+	logs := getCrashingPodsLogs(context.Background(), client, []corev1.Pod{crashing, pending, crashing}, 10)
+
+	want := []containerLogs{{Container: "app", Reason: "Error", ExitCode: 2, Lines: []string{"fake logs"}, previous: true}}
+	assert.Equal(t, [][]containerLogs{want, {}, want}, logs)
+}
+
+func TestGetContainerLogsLines(t *testing.T) {
+	tests := []struct {
+		name string
+		logs containerLogs
+		want []string
+	}{
+		{
+			name: "logs of the previous instance are expected to be indented under the termination",
+			logs: containerLogs{Container: "app", Reason: "Error", ExitCode: 1, Lines: []string{"starting", "panic: boom"}, previous: true},
+			want: []string{"app: Error, exit code 1, logs of the previous instance:", "  starting", "  panic: boom"},
+		},
+		{
+			name: "logs that could not be fetched are expected to show the error",
+			logs: containerLogs{Container: "app", ExitCode: 1, Error: "container not found", previous: true},
+			want: []string{"app: <none>, exit code 1, logs of the previous instance:", "  container not found"},
+		},
+		{
+			name: "empty logs of the terminated instance are expected to show none",
+			logs: containerLogs{Container: "app", Reason: "Error", ExitCode: 1, Lines: []string{}},
+			want: []string{"app: Error, exit code 1, logs of the terminated instance:", "  <none>"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, getContainerLogsLines(tt.logs))
+		})
+	}
+}
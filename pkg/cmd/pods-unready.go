@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
@@ -67,6 +67,7 @@ func (o *UnreadyPodsOptions) Run(ctx context.Context, noHeader bool) error {
 	}
 
 	var matrix [][]string
+	var uids []types.UID
 
 	for _, pod := range pods.Items {
 		if !isPodReady(pod) {
@@ -76,15 +77,20 @@ func (o *UnreadyPodsOptions) Run(ctx context.Context, noHeader bool) error {
 			if o.allNamespaces {
 				row = append([]string{pod.Namespace}, row...)
 			}
+			uids = append(uids, pod.UID)
 			matrix = append(matrix, row)
 		}
 	}
 
 	headers := []string{"NAME", "STATUS", "AGE"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1,19 +1,53 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// schedulingMessagePattern matches the scheduler message summarizing why no node fits a Pod,
+// for example "0/12 nodes are available: 3 Insufficient cpu, 9 node(s) had taint ...".
+var schedulingMessagePattern = regexp.MustCompile(`^0/(\d+) nodes are available: (.*)$`)
+
+// schedulingFailureItemPattern matches an item of the scheduler message, a number of nodes and a reason.
+var schedulingFailureItemPattern = regexp.MustCompile(`^(\d+) (.+)$`)
+
 // UnscheduledPodsOptions embeds JanitorOptions struct.
 type UnscheduledPodsOptions struct {
 	JanitorOptions
+	hints     bool
+	summarize bool
+}
+
+// schedulingBreakdown is a scheduler message parsed into the number of nodes
+// rejected for each reason.
+type schedulingBreakdown struct {
+	Nodes   int                 `json:"nodes"`
+	Reasons []schedulingFailure `json:"reasons"`
+}
+
+// schedulingFailure is a reason why the scheduler rejected some nodes.
+type schedulingFailure struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// schedulingSummary aggregates a scheduling failure reason across the unscheduled Pods.
+type schedulingSummary struct {
+	reason   string
+	pods     int
+	examples []string
 }
 
 // newUnscheduledPodsOptions creates an instance of PendingPodsOptions.
@@ -47,6 +81,8 @@ func newUnscheduledPodsCommand(factory cmdutil.Factory, options JanitorOptions)
 	}
 
 	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().BoolVar(&o.hints, "hints", false, "Show an explanation and the next commands to run for the scheduling failure of every Pod.")
+	cmd.Flags().BoolVar(&o.summarize, "summarize", false, "Aggregate the scheduling failure reasons across all unscheduled Pods.")
 
 	return cmd
 }
@@ -66,26 +102,156 @@ func (o *UnscheduledPodsOptions) Run(ctx context.Context, noHeader bool) error {
 		return err
 	}
 
-	var matrix [][]string
+	var unscheduled []corev1.Pod
+	var conditions []corev1.PodCondition
+	var breakdowns []schedulingBreakdown
 
 	for _, pod := range pods.Items {
 		for _, c := range pod.Status.Conditions {
-			if c.Type == "PodScheduled" && c.Status == "False" {
-				age := getAge(pod.CreationTimestamp)
-				row := []string{pod.Name, c.Reason, c.Message, age}
-				if o.allNamespaces {
-					row = append([]string{pod.Namespace}, row...)
-				}
-				matrix = append(matrix, row)
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+				unscheduled = append(unscheduled, pod)
+				conditions = append(conditions, c)
+				breakdowns = append(breakdowns, parseSchedulingMessage(c.Message))
 			}
 		}
 	}
 
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+
+	if o.summarize {
+		var matrix [][]string
+		for _, summary := range summarizeSchedulingFailures(unscheduled, breakdowns, o.allNamespaces) {
+			row := []string{summary.reason, strconv.Itoa(summary.pods), strings.Join(summary.examples, ",")}
+			matrix = append(matrix, row)
+		}
+
+		headers := []string{"REASON", "PODS", "EXAMPLES"}
+
+		return p.printClusterResults(headers, matrix, nil, "PODS")
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+
+	scheduling := resultDetails{key: "scheduling"}
+	for i, pod := range unscheduled {
+		age := getAge(pod.CreationTimestamp)
+		row := []string{pod.Name, conditions[i].Reason, conditions[i].Message, age}
+		if o.allNamespaces {
+			row = append([]string{pod.Namespace}, row...)
+		}
+		uids = append(uids, pod.UID)
+		matrix = append(matrix, row)
+
+		// The breakdown repeats the message, so it is only part of the structured output.
+		scheduling.lines = append(scheduling.lines, nil)
+		scheduling.values = append(scheduling.values, breakdowns[i])
+	}
+
 	headers := []string{"NAME", "REASON", "MESSAGE", "AGE"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
+	details := []resultDetails{scheduling}
+
+	if o.hints {
+		catalog, err := o.getHintCatalog()
+		if err != nil {
+			return err
+		}
+
+		hints := resultDetails{key: "hints"}
+		for _, pod := range unscheduled {
+			podHints := getPodHints(pod, nil, catalog)
+			hints.lines = append(hints.lines, getPodHintsLines(podHints))
+			hints.values = append(hints.values, podHints)
+		}
+		details = append(details, hints)
+	}
+
+	return p.printResultsWithDetails(headers, matrix, uids, o.namespace, details)
+}
+
+// parseSchedulingMessage parses a scheduler message into the number of nodes
+// rejected for each reason. Reasons may contain commas, such as the taints in
+// "1 node(s) had taint {key: value}, that the pod didn't tolerate", so an item
+// not starting with a number continues the previous one. A message that does
+// not follow the format is returned as a single reason without count.
+func parseSchedulingMessage(message string) schedulingBreakdown {
+	breakdown := schedulingBreakdown{Reasons: []schedulingFailure{}}
+
+	message = strings.TrimSpace(message)
+	if i := strings.Index(message, " preemption:"); i >= 0 {
+		message = message[:i]
+	}
+
+	match := schedulingMessagePattern.FindStringSubmatch(message)
+	if match == nil {
+		if message != "" {
+			breakdown.Reasons = append(breakdown.Reasons, schedulingFailure{Reason: strings.TrimSuffix(message, ".")})
+		}
+		return breakdown
+	}
+
+	breakdown.Nodes, _ = strconv.Atoi(match[1])
+
+	for _, item := range strings.Split(strings.TrimSuffix(match[2], "."), ", ") {
+		if m := schedulingFailureItemPattern.FindStringSubmatch(item); m != nil {
+			count, _ := strconv.Atoi(m[1])
+			breakdown.Reasons = append(breakdown.Reasons, schedulingFailure{Reason: m[2], Count: count})
+			continue
+		}
+
+		if n := len(breakdown.Reasons); n > 0 {
+			breakdown.Reasons[n-1].Reason += ", " + item
+		}
+	}
+
+	return breakdown
+}
+
+// summarizeSchedulingFailures counts the unscheduled Pods rejected for each reason,
+// sorted by decreasing number of Pods. The breakdowns are those of the Pods.
+func summarizeSchedulingFailures(pods []corev1.Pod, breakdowns []schedulingBreakdown, allNamespaces bool) []schedulingSummary {
+	summaries := make(map[string]*schedulingSummary)
+
+	for i, pod := range pods {
+		name := pod.Name
+		if allNamespaces {
+			name = pod.Namespace + "/" + name
+		}
+
+		seen := make(map[string]bool)
+		for _, failure := range breakdowns[i].Reasons {
+			if seen[failure.Reason] {
+				continue
+			}
+			seen[failure.Reason] = true
+
+			summary, ok := summaries[failure.Reason]
+			if !ok {
+				summary = &schedulingSummary{reason: failure.Reason}
+				summaries[failure.Reason] = summary
+			}
+			summary.pods++
+			if len(summary.examples) < maxGroupExamples {
+				summary.examples = append(summary.examples, name)
+			}
+		}
+	}
+
+	var result []schedulingSummary
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].pods != result[j].pods {
+			return result[i].pods > result[j].pods
+		}
+		return result[i].reason < result[j].reason
+	})
 
-	return nil
+	return result
 }
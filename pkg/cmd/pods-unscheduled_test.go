@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseSchedulingMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    schedulingBreakdown
+	}{
+		{
+			name:    "message is expected to be split into reasons with their number of nodes",
+			message: "0/12 nodes are available: 3 Insufficient cpu, 9 node(s) didn't match node selector.",
+			want: schedulingBreakdown{Nodes: 12, Reasons: []schedulingFailure{
+				{Reason: "Insufficient cpu", Count: 3},
+				{Reason: "node(s) didn't match node selector", Count: 9},
+			}},
+		},
+		{
+			name:    "taint containing a comma is expected to stay in a single reason",
+			message: "0/4 nodes are available: 1 node(s) had taint {node-role.kubernetes.io/master: }, that the pod didn't tolerate, 3 Insufficient memory.",
+			want: schedulingBreakdown{Nodes: 4, Reasons: []schedulingFailure{
+				{Reason: "node(s) had taint {node-role.kubernetes.io/master: }, that the pod didn't tolerate", Count: 1},
+				{Reason: "Insufficient memory", Count: 3},
+			}},
+		},
+		{
+			name:    "preemption details are expected to be ignored",
+			message: "0/3 nodes are available: 3 Insufficient memory. preemption: 0/3 nodes are available: 3 No preemption victims found for incoming pod.",
+			want: schedulingBreakdown{Nodes: 3, Reasons: []schedulingFailure{
+				{Reason: "Insufficient memory", Count: 3},
+			}},
+		},
+		{
+			name:    "message in another format is expected to be kept as a single reason",
+			message: `persistentvolumeclaim "data" not found.`,
+			want: schedulingBreakdown{Reasons: []schedulingFailure{
+				{Reason: `persistentvolumeclaim "data" not found`},
+			}},
+		},
+		{
+			name: "empty message is expected to have no reason",
+			want: schedulingBreakdown{Reasons: []schedulingFailure{}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parseSchedulingMessage(tt.message))
+		})
+	}
+}
+
+func TestSummarizeSchedulingFailures(t *testing.T) {
+	pods := []corev1.Pod{newTestPod("shop", "web-0"), newTestPod("shop", "web-1"), newTestPod("batch", "report"), newTestPod("batch", "etl")}
+	breakdowns := []schedulingBreakdown{
+		parseSchedulingMessage("0/3 nodes are available: 1 Insufficient cpu, 2 Insufficient memory."),
+		parseSchedulingMessage("0/3 nodes are available: 3 Insufficient memory."),
+		parseSchedulingMessage("0/3 nodes are available: 3 Insufficient memory."),
+		parseSchedulingMessage("0/3 nodes are available: 3 node(s) didn't match node selector."),
+	}
+
+	tests := []struct {
+		name          string
+		allNamespaces bool
+		want          []schedulingSummary
+	}{
+		{
+			name: "reasons are expected to be sorted by number of Pods",
+			want: []schedulingSummary{
+				{reason: "Insufficient memory", pods: 3, examples: []string{"web-0", "web-1", "report"}},
+				{reason: "Insufficient cpu", pods: 1, examples: []string{"web-0"}},
+				{reason: "node(s) didn't match node selector", pods: 1, examples: []string{"etl"}},
+			},
+		},
+		{
+			name:          "examples are expected to include the namespace across all namespaces",
+			allNamespaces: true,
+			want: []schedulingSummary{
+				{reason: "Insufficient memory", pods: 3, examples: []string{"shop/web-0", "shop/web-1", "batch/report"}},
+				{reason: "Insufficient cpu", pods: 1, examples: []string{"shop/web-0"}},
+				{reason: "node(s) didn't match node selector", pods: 1, examples: []string{"batch/etl"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, summarizeSchedulingFailures(pods, breakdowns, tt.allNamespaces))
+		})
+	}
+}
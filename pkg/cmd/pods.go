@@ -14,6 +14,7 @@ func newPodsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Comm
 		SilenceUsage: true,
 	}
 
+	cmd.AddCommand(newBrokenRefsPodsCommand(factory, options))
 	cmd.AddCommand(newUnhealthyPodsCommand(factory, options))
 	cmd.AddCommand(newUnreadyPodsCommand(factory, options))
 	cmd.AddCommand(newStatusPodsCommand(factory, options))
@@ -1,20 +1,44 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+const (
+	// annotationIsDefaultStorageClass marks a StorageClass as the default of the cluster.
+	annotationIsDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
+	// noProvisioner is the provisioner of StorageClasses backed by statically provisioned volumes.
+	noProvisioner = "kubernetes.io/no-provisioner"
+)
+
 // PendingPVCsOptions embeds JanitorOptions struct.
 type PendingPVCsOptions struct {
 	JanitorOptions
 }
 
+// pendingPVCContext holds the objects a Pending PersistentVolumeClaim is checked against.
+type pendingPVCContext struct {
+	classes      map[string]storagev1.StorageClass
+	defaultClass string
+	// csiDrivers is nil when the CSIDrivers cannot be listed.
+	csiDrivers   map[string]bool
+	availablePVs []corev1.PersistentVolume
+	consumers    map[types.NamespacedName]bool
+}
+
 // newPendingPVCsOptions create a instance of PendingPVCsOptions.
 func newPendingPVCsOptions(options JanitorOptions) *PendingPVCsOptions {
 	return &PendingPVCsOptions{
@@ -28,7 +52,7 @@ func newPendingPVCsCommand(factory cmdutil.Factory, options JanitorOptions) *cob
 
 	cmd := &cobra.Command{
 		Use:          "pending",
-		Short:        "List PersistentVolumeClaims in a pending state (unbound)",
+		Short:        "List PersistentVolumeClaims in a pending state (unbound) and why they are stuck",
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.Complete(factory, c); err != nil {
@@ -62,24 +86,236 @@ func (o *PendingPVCsOptions) Run(ctx context.Context, noHeader bool) error {
 		return err
 	}
 
+	var pending []corev1.PersistentVolumeClaim
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase == corev1.ClaimPending {
+			pending = append(pending, pvc)
+		}
+	}
+
 	var matrix [][]string
+	var uids []types.UID
+
+	if len(pending) > 0 {
+		pc, err := getPendingPVCContext(ctx, client, o.namespace, o.Streams.ErrOut)
+		if err != nil {
+			return err
+		}
+
+		events, err := getEvents(ctx, client, o.namespace, "PersistentVolumeClaim", "")
+		if err != nil {
+			return err
+		}
+
+		for _, pvc := range pending {
+			class := getPVCStorageClassName(pvc, pc.defaultClass)
+			if class == "" {
+				class = "<none>"
+			}
+
+			reason := strings.Join(getPVCPendingReasons(pvc, pc, events[pvc.UID]), "; ")
+			if reason == "" {
+				reason = "<unknown>"
+			}
 
-	for _, pvc := range pvcs.Items {
-		if pvc.Status.Phase == "Pending" {
 			age := getAge(pvc.CreationTimestamp)
-			row := []string{pvc.Name, age}
+			row := []string{pvc.Name, class, reason, age}
 			if o.allNamespaces {
 				row = append([]string{pvc.Namespace}, row...)
 			}
+			uids = append(uids, pvc.UID)
 			matrix = append(matrix, row)
 		}
 	}
 
-	headers := []string{"NAME", "AGE"}
+	headers := []string{"NAME", "STORAGECLASS", "REASON", "AGE"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// getPendingPVCContext collects the StorageClasses, CSIDrivers, Available
+// PersistentVolumes and Pods of the namespace needed to explain Pending
+// PersistentVolumeClaims. A warning is written to errOut when the CSIDrivers
+// cannot be listed.
+func getPendingPVCContext(ctx context.Context, client kubernetes.Interface, namespace string, errOut io.Writer) (pendingPVCContext, error) {
+	pc := pendingPVCContext{
+		classes:   make(map[string]storagev1.StorageClass),
+		consumers: make(map[types.NamespacedName]bool),
+	}
+
+	classes, err := client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return pc, err
+	}
+	for _, class := range classes.Items {
+		pc.classes[class.Name] = class
+		if class.Annotations[annotationIsDefaultStorageClass] == "true" {
+			pc.defaultClass = class.Name
+		}
+	}
+
+	drivers, err := client.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err) || apierrors.IsForbidden(err):
+		fmt.Fprintf(errOut, "Warning: skipping the CSI driver check, CSIDrivers cannot be listed: %v\n", err)
+	case err != nil:
+		return pc, err
+	default:
+		pc.csiDrivers = make(map[string]bool)
+		for _, driver := range drivers.Items {
+			pc.csiDrivers[driver.Name] = true
+		}
+	}
+
+	pvs, err := client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return pc, err
+	}
+	pc.availablePVs = filterPVsByPhase(pvs.Items, corev1.VolumeAvailable)
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return pc, err
+	}
+	for _, pod := range pods.Items {
+		for _, claim := range getPodClaimNames(pod) {
+			pc.consumers[types.NamespacedName{Namespace: pod.Namespace, Name: claim}] = true
+		}
+	}
+
+	return pc, nil
+}
+
+// getPVCStorageClassName returns the StorageClass the claim is provisioned from.
+func getPVCStorageClassName(pvc corev1.PersistentVolumeClaim, defaultClass string) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return defaultClass
+}
+
+// getPVCPendingReasons explains why a PersistentVolumeClaim is still Pending.
+// The events are the Events of the claim, sorted from newest to oldest.
+func getPVCPendingReasons(pvc corev1.PersistentVolumeClaim, pc pendingPVCContext, events []corev1.Event) []string {
+	var reasons []string
+
+	provisioningFailed := false
+	for _, event := range events {
+		if event.Reason == "ProvisioningFailed" || event.Reason == "ExternalProvisioning" {
+			reasons = append(reasons, event.Reason+": "+event.Message)
+			provisioningFailed = event.Reason == "ProvisioningFailed"
+			break
+		}
+	}
+
+	className := getPVCStorageClassName(pvc, pc.defaultClass)
+	if pvc.Spec.StorageClassName == nil && className == "" {
+		reasons = append(reasons, "NoDefaultStorageClass")
+	}
+
+	static := className == ""
+	if className != "" {
+		class, ok := pc.classes[className]
+		if !ok {
+			return append(reasons, fmt.Sprintf("StorageClassNotFound(%s)", className))
+		}
+
+		if class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+			if !pc.consumers[types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}] {
+				reasons = append(reasons, "WaitForFirstConsumer: no Pod uses this claim")
+			}
+		}
+
+		// Only a CSIDriver object registers a driver, one that is merely installed
+		// on the Nodes is reported too. A failed provisioning is explained by its Event.
+		switch {
+		case class.Provisioner == noProvisioner:
+			static = true
+		case !strings.HasPrefix(class.Provisioner, "kubernetes.io/") && pc.csiDrivers != nil && !pc.csiDrivers[class.Provisioner] && !provisioningFailed:
+			reasons = append(reasons, fmt.Sprintf("CSIDriverMissing(%s)", class.Provisioner))
+		}
+	}
+
+	if static && !hasMatchingPV(pvc, className, pc.availablePVs) {
+		reasons = append(reasons, "NoMatchingAvailablePV")
+	}
+
+	return reasons
+}
+
+// hasMatchingPV checks whether one of the Available PersistentVolumes can satisfy
+// the claim: its class, capacity, access modes, volume mode and the claim's label
+// selector must match, and it must not be pre-bound to another claim.
+func hasMatchingPV(pvc corev1.PersistentVolumeClaim, className string, pvs []corev1.PersistentVolume) bool {
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+
+	selector := labels.Everything()
+	if pvc.Spec.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(pvc.Spec.Selector)
+		if err != nil {
+			return false
+		}
+	}
+
+	for _, pv := range pvs {
+		if pv.Spec.StorageClassName != className {
+			continue
+		}
+
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			if ref.Namespace != pvc.Namespace || ref.Name != pvc.Name || (ref.UID != "" && ref.UID != pvc.UID) {
+				continue
+			}
+		}
+
+		if !selector.Matches(labels.Set(pv.Labels)) {
+			continue
+		}
+
+		if getVolumeMode(pvc.Spec.VolumeMode) != getVolumeMode(pv.Spec.VolumeMode) {
+			continue
+		}
+
+		capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+		if capacity.Cmp(request) < 0 {
+			continue
+		}
+
+		supported := true
+		for _, mode := range pvc.Spec.AccessModes {
+			found := false
+			for _, pvMode := range pv.Spec.AccessModes {
+				if mode == pvMode {
+					found = true
+					break
+				}
+			}
+			if !found {
+				supported = false
+				break
+			}
+		}
+
+		if supported {
+			return true
+		}
+	}
+	return false
+}
+
+// getVolumeMode returns the volume mode, which defaults to Filesystem.
+func getVolumeMode(mode *corev1.PersistentVolumeMode) corev1.PersistentVolumeMode {
+	if mode == nil {
+		return corev1.PersistentVolumeFilesystem
+	}
+	return *mode
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetPVCPendingReasons(t *testing.T) {
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+
+	block := corev1.PersistentVolumeBlock
+
+	fast := newTestPV("local-fast", "local", "20Gi")
+	fast.Labels = map[string]string{"disk": "ssd"}
+	raw := newTestPV("local-raw", "local", "50Gi")
+	raw.Spec.VolumeMode = &block
+	reserved := newTestPV("local-reserved", "local", "100Gi")
+	reserved.Spec.ClaimRef = &corev1.ObjectReference{Namespace: testNamespace, Name: "reserved"}
+
+	withSelector := newTestPVC(testNamespace, "data", stringPtr("local"), "10Gi")
+	withSelector.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd"}}
+	withUnmatchedSelector := newTestPVC(testNamespace, "data", stringPtr("local"), "1Gi")
+	withUnmatchedSelector.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "nvme"}}
+	withBlockMode := newTestPVC(testNamespace, "data", stringPtr("local"), "30Gi")
+	withBlockMode.Spec.VolumeMode = &block
+
+	pc := pendingPVCContext{
+		classes: map[string]storagev1.StorageClass{
+			"standard": {Provisioner: "kubernetes.io/gce-pd"},
+			"csi":      {Provisioner: "ebs.csi.aws.com"},
+			"efs":      {Provisioner: "efs.csi.aws.com"},
+			"local":    {Provisioner: noProvisioner},
+			"lazy":     {Provisioner: "kubernetes.io/gce-pd", VolumeBindingMode: &waitForFirstConsumer},
+		},
+		csiDrivers:   map[string]bool{"efs.csi.aws.com": true},
+		availablePVs: []corev1.PersistentVolume{newTestPV("local-1", "local", "5Gi"), fast, raw, reserved},
+		consumers:    map[types.NamespacedName]bool{},
+	}
+
+	tests := []struct {
+		name           string
+		pvc            corev1.PersistentVolumeClaim
+		events         []corev1.Event
+		unknownDrivers bool
+		want           []string
+	}{
+		{
+			name: "claim with a provisioning failure is expected to report the Event",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("standard"), "1Gi"),
+			events: []corev1.Event{
+				{Reason: "ProvisioningFailed", Message: "quota exceeded"},
+				{Reason: "ExternalProvisioning", Message: "waiting"},
+			},
+			want: []string{"ProvisioningFailed: quota exceeded"},
+		},
+		{
+			name: "claim referencing a missing StorageClass is expected to be reported",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("gold"), "1Gi"),
+			want: []string{"StorageClassNotFound(gold)"},
+		},
+		{
+			name: "claim without a class and no default StorageClass is expected to be reported",
+			pvc:  newTestPVC(testNamespace, "data", nil, "1Gi"),
+			want: []string{"NoDefaultStorageClass", "NoMatchingAvailablePV"},
+		},
+		{
+			name: "claim waiting for a consumer without any Pod is expected to be reported",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("lazy"), "1Gi"),
+			want: []string{"WaitForFirstConsumer: no Pod uses this claim"},
+		},
+		{
+			name: "claim using a provisioner without a CSIDriver is expected to be reported",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("csi"), "1Gi"),
+			want: []string{"CSIDriverMissing(ebs.csi.aws.com)"},
+		},
+		{
+			name: "claim using a registered CSI driver is expected to have no reason",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("efs"), "1Gi"),
+			want: nil,
+		},
+		{
+			name:   "claim whose external provisioner failed is expected to report the Event only",
+			pvc:    newTestPVC(testNamespace, "data", stringPtr("csi"), "1Gi"),
+			events: []corev1.Event{{Reason: "ProvisioningFailed", Message: "quota exceeded"}},
+			want:   []string{"ProvisioningFailed: quota exceeded"},
+		},
+		{
+			name:           "claim using an external provisioner is expected to have no reason when the CSIDrivers cannot be listed",
+			pvc:            newTestPVC(testNamespace, "data", stringPtr("csi"), "1Gi"),
+			unknownDrivers: true,
+			want:           nil,
+		},
+		{
+			name: "claim on a static class with a matching PersistentVolume is expected to have no reason",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("local"), "5Gi"),
+			want: nil,
+		},
+		{
+			name: "claim on a static class larger than any PersistentVolume is expected to be reported",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("local"), "200Gi"),
+			want: []string{"NoMatchingAvailablePV"},
+		},
+		{
+			name: "claim selecting the labels of a PersistentVolume is expected to have no reason",
+			pvc:  withSelector,
+			want: nil,
+		},
+		{
+			name: "claim selecting labels no PersistentVolume has is expected to be reported",
+			pvc:  withUnmatchedSelector,
+			want: []string{"NoMatchingAvailablePV"},
+		},
+		{
+			name: "claim for a block volume is expected to match only a block PersistentVolume",
+			pvc:  withBlockMode,
+			want: nil,
+		},
+		{
+			name: "claim larger than every Filesystem PersistentVolume is not expected to match a block one",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("local"), "30Gi"),
+			want: []string{"NoMatchingAvailablePV"},
+		},
+		{
+			name: "claim a PersistentVolume is pre-bound to is expected to have no reason",
+			pvc:  newTestPVC(testNamespace, "reserved", stringPtr("local"), "100Gi"),
+			want: nil,
+		},
+		{
+			name: "claim matching a PersistentVolume pre-bound to another claim is expected to be reported",
+			pvc:  newTestPVC(testNamespace, "data", stringPtr("local"), "100Gi"),
+			want: []string{"NoMatchingAvailablePV"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			pc := pc
+			if tc.unknownDrivers {
+				pc.csiDrivers = nil
+			}
+			got := getPVCPendingReasons(tc.pvc, pc, tc.events)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPendingPVCsRunWithoutCSIDrivers(t *testing.T) {
+	class := storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "local-path"}, Provisioner: "rancher.io/local-path"}
+	pvc := newTestPVC(testNamespace, "data", stringPtr(class.Name), "1Gi")
+	pvc.Status.Phase = corev1.ClaimPending
+
+	client := fake.NewSimpleClientset(&class, &pvc)
+	client.PrependReactor("list", "csidrivers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(action.GetResource().GroupResource(), "", nil)
+	})
+
+	options, _, out, errOut := newFakeJanitorOptions(client)
+	err := newPendingPVCsOptions(options).Run(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"data", "local-path", "<unknown>"}, strings.Fields(out.String())[:3])
+	assert.Contains(t, errOut.String(), "Warning: skipping the CSI driver check")
+}
+
+func TestPendingPVCsRunWithDriverOnlyOnNodes(t *testing.T) {
+	class := storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "ebs"}, Provisioner: "ebs.csi.aws.com"}
+	node := storagev1.CSINode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       storagev1.CSINodeSpec{Drivers: []storagev1.CSINodeDriver{{Name: class.Provisioner, NodeID: "node-a"}}},
+	}
+	pvc := newTestPVC(testNamespace, "data", stringPtr(class.Name), "1Gi")
+	pvc.Status.Phase = corev1.ClaimPending
+
+	options, _, out, errOut := newFakeJanitorOptions(fake.NewSimpleClientset(&class, &node, &pvc))
+	err := newPendingPVCsOptions(options).Run(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"data", "ebs", "CSIDriverMissing(ebs.csi.aws.com)"}, strings.Fields(out.String())[:3])
+	assert.Empty(t, errOut.String())
+}
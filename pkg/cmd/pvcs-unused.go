@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// UnusedPVCsOptions embeds JanitorOptions struct.
+type UnusedPVCsOptions struct {
+	JanitorOptions
+}
+
+// newUnusedPVCsOptions creates an instance of UnusedPVCsOptions.
+func newUnusedPVCsOptions(options JanitorOptions) *UnusedPVCsOptions {
+	return &UnusedPVCsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newUnusedPVCsCommand returns a cobra command wrapping UnusedPVCsOptions.
+func newUnusedPVCsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newUnusedPVCsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "unused",
+		Short:        "List Bound PersistentVolumeClaims that are not mounted by any Pod",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run lists Bound PersistentVolumeClaims that no Pod uses.
+func (o *UnusedPVCsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	pods, err := client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	consumers := make(map[types.NamespacedName]bool)
+	for _, pod := range pods.Items {
+		for _, claim := range getPodClaimNames(pod) {
+			consumers[types.NamespacedName{Namespace: pod.Namespace, Name: claim}] = true
+		}
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+	var unused []corev1.PersistentVolumeClaim
+
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+
+		reason, ok := getUnusedPVCReason(pvc, consumers, statefulSets.Items)
+		if !ok {
+			continue
+		}
+		unused = append(unused, pvc)
+
+		class := "<none>"
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			class = *pvc.Spec.StorageClassName
+		}
+
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		age := getAge(pvc.CreationTimestamp)
+		row := []string{pvc.Name, capacity.String(), class, reason, age}
+		if o.allNamespaces {
+			row = append([]string{pvc.Namespace}, row...)
+		}
+		uids = append(uids, pvc.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "CAPACITY", "STORAGECLASS", "REASON", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printTables(
+		resultTable{name: "claims", headers: headers, matrix: matrix, uids: uids, namespaced: true, namespace: o.namespace},
+		resultTable{name: "unusedCapacity", headers: []string{"NAMESPACE", "CLAIMS", "TOTAL CAPACITY"}, matrix: getUnusedPVCCapacity(unused), numeric: sets.NewString("CLAIMS"), optional: true},
+	)
+}
+
+// getUnusedPVCReason checks whether a Bound PersistentVolumeClaim is unused and explains why.
+// Claims created from a StatefulSet volumeClaimTemplate are only reported when their
+// ordinal is beyond the current number of replicas, since lower ordinals are reattached
+// once their Pod is recreated.
+func getUnusedPVCReason(pvc corev1.PersistentVolumeClaim, consumers map[types.NamespacedName]bool, statefulSets []appsv1.StatefulSet) (string, bool) {
+	if consumers[types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}] {
+		return "", false
+	}
+
+	for _, sts := range statefulSets {
+		if sts.Namespace != pvc.Namespace {
+			continue
+		}
+
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+
+		for _, template := range sts.Spec.VolumeClaimTemplates {
+			prefix := template.Name + "-" + sts.Name + "-"
+			if !strings.HasPrefix(pvc.Name, prefix) {
+				continue
+			}
+
+			ordinal, err := strconv.Atoi(strings.TrimPrefix(pvc.Name, prefix))
+			if err != nil {
+				continue
+			}
+
+			if int32(ordinal) < replicas {
+				return "", false
+			}
+			return fmt.Sprintf("ScaledDown(statefulset/%s, replicas %d)", sts.Name, replicas), true
+		}
+	}
+
+	return "NotMounted", true
+}
+
+// getUnusedPVCCapacity returns the number of claims and their total capacity
+// for each namespace, sorted by namespace.
+func getUnusedPVCCapacity(pvcs []corev1.PersistentVolumeClaim) [][]string {
+	var namespaces []string
+	var capacities []corev1.ResourceList
+	for _, pvc := range pvcs {
+		namespaces = append(namespaces, pvc.Namespace)
+		capacities = append(capacities, pvc.Status.Capacity)
+	}
+	return getStorageTotals(namespaces, capacities)
+}
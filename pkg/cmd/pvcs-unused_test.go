@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetUnusedPVCReason(t *testing.T) {
+	replicas := int32(2)
+
+	statefulSets := []appsv1.StatefulSet{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             &replicas,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+		},
+	}}
+
+	consumers := map[types.NamespacedName]bool{
+		{Namespace: "default", Name: "data-db-0"}: true,
+	}
+
+	tests := []struct {
+		name       string
+		pvc        corev1.PersistentVolumeClaim
+		wantReason string
+		wantOK     bool
+	}{
+		{
+			name:   "claim mounted by a Pod is expected to be used",
+			pvc:    newTestPVC(testNamespace, "data-db-0", nil, "1Gi"),
+			wantOK: false,
+		},
+		{
+			name:   "StatefulSet claim within the replicas is expected to be reattached",
+			pvc:    newTestPVC(testNamespace, "data-db-1", nil, "1Gi"),
+			wantOK: false,
+		},
+		{
+			name:       "StatefulSet claim beyond the replicas is expected to be reported",
+			pvc:        newTestPVC(testNamespace, "data-db-4", nil, "1Gi"),
+			wantReason: "ScaledDown(statefulset/db, replicas 2)",
+			wantOK:     true,
+		},
+		{
+			name:       "claim not mounted by any Pod is expected to be reported",
+			pvc:        newTestPVC(testNamespace, "scratch", nil, "1Gi"),
+			wantReason: "NotMounted",
+			wantOK:     true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			reason, ok := getUnusedPVCReason(tc.pvc, consumers, statefulSets)
+			assert.Equal(t, tc.wantReason, reason)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
+
+func TestGetUnusedPVCCapacity(t *testing.T) {
+	pvcs := []corev1.PersistentVolumeClaim{
+		newTestPVC("production", "logs", nil, "10Gi"),
+		newTestPVC("default", "data", nil, "1Gi"),
+		newTestPVC("production", "data", nil, "20Gi"),
+	}
+	for i := range pvcs {
+		pvcs[i].Status.Capacity = pvcs[i].Spec.Resources.Requests
+	}
+
+	got := getUnusedPVCCapacity(pvcs)
+
+	assert.Equal(t, [][]string{
+		{"default", "1", "1Gi"},
+		{"production", "2", "30Gi"},
+	}, got)
+}
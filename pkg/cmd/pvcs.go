@@ -15,6 +15,7 @@ func newPVCsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Comm
 	}
 
 	cmd.AddCommand(newPendingPVCsCommand(factory, options))
+	cmd.AddCommand(newUnusedPVCsCommand(factory, options))
 
 	return cmd
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// FailedPVsOptions embeds JanitorOptions struct.
+type FailedPVsOptions struct {
+	JanitorOptions
+}
+
+// newFailedPVsOptions creates an instance of FailedPVsOptions.
+func newFailedPVsOptions(options JanitorOptions) *FailedPVsOptions {
+	return &FailedPVsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newFailedPVsCommand returns a cobra command wrapping FailedPVsOptions.
+func newFailedPVsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newFailedPVsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "failed",
+		Short:        "List PersistentVolumes whose automatic reclamation failed",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// Run lists PersistentVolumes in a Failed phase.
+func (o *FailedPVsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	pvs, err := client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	failed := filterPVsByPhase(pvs.Items, corev1.VolumeFailed)
+
+	var matrix [][]string
+	var uids []types.UID
+	for _, pv := range failed {
+		uids = append(uids, pv.UID)
+		matrix = append(matrix, append(getPVRow(pv), pv.Status.Message))
+	}
+
+	headers := []string{"NAME", "FORMER CLAIM", "CAPACITY", "RECLAIM POLICY", "STORAGECLASS", "FAILED", "AGE", "MESSAGE"}
+
+	p, err := o.newPrinter(ctx, "", noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printTables(
+		resultTable{name: "volumes", headers: headers, matrix: matrix, uids: uids},
+		resultTable{name: "wastedCapacity", headers: []string{"STORAGECLASS", "VOLUMES", "WASTED CAPACITY"}, matrix: getWastedCapacity(failed), numeric: sets.NewString("VOLUMES"), optional: true},
+	)
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	reclaimActionDelete        = "Delete"
+	reclaimActionClearClaimRef = "ClearClaimRef"
+)
+
+// ReclaimPVsOptions embeds JanitorOptions struct.
+type ReclaimPVsOptions struct {
+	JanitorOptions
+	delete    bool
+	dryRun    bool
+	backupDir string
+}
+
+// newReclaimPVsOptions creates an instance of ReclaimPVsOptions.
+func newReclaimPVsOptions(options JanitorOptions) *ReclaimPVsOptions {
+	return &ReclaimPVsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newReclaimPVsCommand returns a cobra command wrapping ReclaimPVsOptions.
+func newReclaimPVsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newReclaimPVsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "reclaim [NAME...]",
+		Short:        "Make Released PersistentVolumes Available again, or delete them",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, args, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.delete, "delete", false, "Delete the PersistentVolumes instead of clearing their claimRef.")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only submit server-side dry-run requests, without persisting any change.")
+	cmd.Flags().StringVar(&o.backupDir, "backup-dir", ".", "Directory where the manifests of the PersistentVolumes are saved before they are changed.")
+
+	return cmd
+}
+
+// Run reclaims the given PersistentVolumes, or all Released PersistentVolumes when no names are given.
+func (o *ReclaimPVsOptions) Run(ctx context.Context, names []string, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	var pvs []corev1.PersistentVolume
+	if len(names) > 0 {
+		for _, name := range names {
+			pv, err := client.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			pvs = append(pvs, *pv)
+		}
+	} else {
+		list, err := client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		pvs = filterPVsByPhase(list.Items, corev1.VolumeReleased)
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+	var reclaimable []corev1.PersistentVolume
+
+	for _, pv := range pvs {
+		claimExists := false
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			_, err := client.CoreV1().PersistentVolumeClaims(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			claimExists = err == nil
+		}
+
+		action, ok := getPVReclaimAction(pv, claimExists, o.delete)
+		if ok {
+			reclaimable = append(reclaimable, pv)
+		}
+
+		row := getPVRow(pv)
+		uids = append(uids, pv.UID)
+		matrix = append(matrix, []string{row[0], row[1], row[2], row[4], action})
+	}
+
+	headers := []string{"NAME", "FORMER CLAIM", "CAPACITY", "STORAGECLASS", "ACTION"}
+
+	p, err := o.newPrinter(ctx, "", noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printClusterResults(headers, matrix, uids); err != nil {
+		return err
+	}
+
+	if len(reclaimable) == 0 {
+		return nil
+	}
+
+	var dryRun []string
+	suffix := ""
+	if o.dryRun {
+		dryRun = []string{metav1.DryRunAll}
+		suffix = " (server dry run)"
+	} else {
+		ok, err := confirm(o.Streams.In, o.Streams.ErrOut, fmt.Sprintf("Reclaim %d PersistentVolume(s)?", len(reclaimable)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(o.Streams.ErrOut, "Aborted")
+			return nil
+		}
+	}
+
+	// A PersistentVolume failing to be reclaimed, for example because it was
+	// bound again since it was listed, does not stop the others.
+	var errs []error
+	for _, pv := range reclaimable {
+		if !o.dryRun {
+			pv.APIVersion, pv.Kind = "v1", "PersistentVolume"
+			path, err := backupObject(o.backupDir, pv.Kind, "", pv.Name, pv)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to back up persistentvolume/%s: %v", pv.Name, err))
+				continue
+			}
+			fmt.Fprintf(o.Streams.ErrOut, "persistentvolume/%s backed up to %s\n", pv.Name, path)
+		}
+
+		if o.delete {
+			uid, resourceVersion := pv.UID, pv.ResourceVersion
+			options := metav1.DeleteOptions{
+				DryRun:        dryRun,
+				Preconditions: &metav1.Preconditions{UID: &uid, ResourceVersion: &resourceVersion},
+			}
+			if err := client.CoreV1().PersistentVolumes().Delete(ctx, pv.Name, options); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete persistentvolume/%s: %v", pv.Name, err))
+				continue
+			}
+			fmt.Fprintf(o.Streams.ErrOut, "persistentvolume/%s deleted%s\n", pv.Name, suffix)
+			continue
+		}
+
+		patch, err := getClearClaimRefPatch(pv)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, err = client.CoreV1().PersistentVolumes().Patch(ctx, pv.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to reclaim persistentvolume/%s: %v", pv.Name, err))
+			continue
+		}
+		fmt.Fprintf(o.Streams.ErrOut, "persistentvolume/%s reclaimed%s\n", pv.Name, suffix)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// getClearClaimRefPatch returns a merge patch clearing the claimRef of the
+// PersistentVolume. The patch carries the uid and resourceVersion the volume
+// was listed with, so it fails with a conflict instead of releasing a volume
+// that was bound again or recreated meanwhile.
+func getClearClaimRefPatch(pv corev1.PersistentVolume) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid":             pv.UID,
+			"resourceVersion": pv.ResourceVersion,
+		},
+		"spec": map[string]interface{}{
+			"claimRef": nil,
+		},
+	})
+}
+
+// getPVReclaimAction returns the action to take on a PersistentVolume and
+// whether it can be reclaimed at all.
+func getPVReclaimAction(pv corev1.PersistentVolume, claimExists, delete bool) (string, bool) {
+	if pv.Status.Phase != corev1.VolumeReleased {
+		return fmt.Sprintf("Skip: phase is %s", pv.Status.Phase), false
+	}
+
+	if claimExists {
+		return "Skip: former claim still exists", false
+	}
+
+	if delete {
+		return reclaimActionDelete, true
+	}
+	return reclaimActionClearClaimRef, true
+}
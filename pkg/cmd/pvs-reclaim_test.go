@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetPVReclaimAction(t *testing.T) {
+	released := corev1.PersistentVolume{Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased}}
+	bound := corev1.PersistentVolume{Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound}}
+
+	tests := []struct {
+		name        string
+		pv          corev1.PersistentVolume
+		claimExists bool
+		delete      bool
+		wantAction  string
+		wantOK      bool
+	}{
+		{
+			name:       "Released PersistentVolume is expected to have its claimRef cleared",
+			pv:         released,
+			wantAction: reclaimActionClearClaimRef,
+			wantOK:     true,
+		},
+		{
+			name:       "Released PersistentVolume is expected to be deleted with --delete",
+			pv:         released,
+			delete:     true,
+			wantAction: reclaimActionDelete,
+			wantOK:     true,
+		},
+		{
+			name:        "PersistentVolume whose former claim exists is expected to be skipped",
+			pv:          released,
+			claimExists: true,
+			wantAction:  "Skip: former claim still exists",
+			wantOK:      false,
+		},
+		{
+			name:       "Bound PersistentVolume is expected to be skipped",
+			pv:         bound,
+			delete:     true,
+			wantAction: "Skip: phase is Bound",
+			wantOK:     false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			action, ok := getPVReclaimAction(tc.pv, tc.claimExists, tc.delete)
+			assert.Equal(t, tc.wantAction, action)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
+
+func TestGetClearClaimRefPatch(t *testing.T) {
+	pv := newTestPV("pv-1", "standard", "10Gi")
+	pv.ResourceVersion = "7"
+
+	patch, err := getClearClaimRefPatch(pv)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"metadata":{"resourceVersion":"7","uid":"pv-1"},"spec":{"claimRef":null}}`, string(patch))
+}
+
+func TestReclaimPVsRun(t *testing.T) {
+	released := newTestPV("pv-released", "standard", "10Gi")
+	released.ResourceVersion = "7"
+	released.Spec.ClaimRef = &corev1.ObjectReference{Namespace: testNamespace, Name: "data"}
+	released.Status.Phase = corev1.VolumeReleased
+
+	rebound := newTestPV("pv-rebound", "standard", "10Gi")
+	rebound.ResourceVersion = "9"
+	rebound.Spec.ClaimRef = &corev1.ObjectReference{Namespace: testNamespace, Name: "logs"}
+	rebound.Status.Phase = corev1.VolumeReleased
+
+	tests := []struct {
+		name   string
+		delete bool
+		verb   string
+	}{
+		{name: "claimRef is expected to be cleared with the version of the listed volume", verb: "patch"},
+		{name: "volume is expected to be deleted with the version of the listed volume as precondition", delete: true, verb: "delete"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := fake.NewSimpleClientset(&released, &rebound)
+
+			// pv-rebound is bound again between the list and its change, which
+			// the preconditions turn into a conflict.
+			client.PrependReactor(tt.verb, "persistentvolumes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.(interface{ GetName() string }).GetName() == rebound.Name {
+					return true, nil, apierrors.NewConflict(action.GetResource().GroupResource(), rebound.Name, nil)
+				}
+				return false, nil, nil
+			})
+
+			options, in, _, errOut := newFakeJanitorOptions(client)
+			in.WriteString("y\n")
+			o := newReclaimPVsOptions(options)
+			o.delete = tt.delete
+			o.backupDir = t.TempDir()
+
+			err := o.Run(context.Background(), nil, true)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "persistentvolume/pv-rebound")
+
+			backups, err := ioutil.ReadDir(o.backupDir)
+			assert.NoError(t, err)
+			assert.Len(t, backups, 2)
+
+			pv, err := client.CoreV1().PersistentVolumes().Get(context.Background(), released.Name, metav1.GetOptions{})
+			if tt.delete {
+				assert.True(t, apierrors.IsNotFound(err))
+				assert.Contains(t, errOut.String(), "persistentvolume/pv-released deleted")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Nil(t, pv.Spec.ClaimRef)
+			assert.Contains(t, errOut.String(), "persistentvolume/pv-released reclaimed")
+
+			for _, action := range client.Actions() {
+				if patch, ok := action.(k8stesting.PatchAction); ok && patch.GetName() == released.Name {
+					assert.Contains(t, string(patch.GetPatch()), `"resourceVersion":"7"`)
+				}
+			}
+		})
+	}
+}
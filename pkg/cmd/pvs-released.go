@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// ReleasedPVsOptions embeds JanitorOptions struct.
+type ReleasedPVsOptions struct {
+	JanitorOptions
+}
+
+// newReleasedPVsOptions creates an instance of ReleasedPVsOptions.
+func newReleasedPVsOptions(options JanitorOptions) *ReleasedPVsOptions {
+	return &ReleasedPVsOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newReleasedPVsCommand returns a cobra command wrapping ReleasedPVsOptions.
+func newReleasedPVsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newReleasedPVsOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "released",
+		Short:        "List PersistentVolumes that were released by their claim but not reclaimed",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// Run lists PersistentVolumes in a Released phase.
+func (o *ReleasedPVsOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	pvs, err := client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	released := filterPVsByPhase(pvs.Items, corev1.VolumeReleased)
+
+	var matrix [][]string
+	var uids []types.UID
+	for _, pv := range released {
+		uids = append(uids, pv.UID)
+		matrix = append(matrix, getPVRow(pv))
+	}
+
+	headers := []string{"NAME", "FORMER CLAIM", "CAPACITY", "RECLAIM POLICY", "STORAGECLASS", "RELEASED", "AGE"}
+
+	p, err := o.newPrinter(ctx, "", noHeader)
+	if err != nil {
+		return err
+	}
+	return p.printTables(
+		resultTable{name: "volumes", headers: headers, matrix: matrix, uids: uids},
+		resultTable{name: "wastedCapacity", headers: []string{"STORAGECLASS", "VOLUMES", "WASTED CAPACITY"}, matrix: getWastedCapacity(released), numeric: sets.NewString("VOLUMES"), optional: true},
+	)
+}
+
+// filterPVsByPhase returns the PersistentVolumes that are in the given phase.
+func filterPVsByPhase(pvs []corev1.PersistentVolume, phase corev1.PersistentVolumePhase) []corev1.PersistentVolume {
+	var filtered []corev1.PersistentVolume
+	for _, pv := range pvs {
+		if pv.Status.Phase == phase {
+			filtered = append(filtered, pv)
+		}
+	}
+	return filtered
+}
+
+// getPVRow returns the columns describing a PersistentVolume that lost its claim.
+func getPVRow(pv corev1.PersistentVolume) []string {
+	claim := "<none>"
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		claim = ref.Namespace + "/" + ref.Name
+	}
+
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+
+	released := "<unknown>"
+	if ts := getPVPhaseTransitionTime(pv); !ts.IsZero() {
+		released = getAge(ts)
+	}
+
+	return []string{pv.Name, claim, capacity.String(), string(pv.Spec.PersistentVolumeReclaimPolicy), pv.Spec.StorageClassName, released, getAge(pv.CreationTimestamp)}
+}
+
+// getPVPhaseTransitionTime approximates when the PersistentVolume entered
+// its current phase using the last update of the manager owning its phase.
+func getPVPhaseTransitionTime(pv corev1.PersistentVolume) metav1.Time {
+	var latest metav1.Time
+	for _, entry := range pv.ManagedFields {
+		if entry.Operation != metav1.ManagedFieldsOperationUpdate || entry.Time == nil || entry.FieldsV1 == nil {
+			continue
+		}
+		if bytes.Contains(entry.FieldsV1.Raw, []byte(`"f:phase"`)) && latest.Before(entry.Time) {
+			latest = *entry.Time
+		}
+	}
+	return latest
+}
+
+// getWastedCapacity returns the number of PersistentVolumes and their total
+// capacity for each StorageClass, sorted by StorageClass name.
+func getWastedCapacity(pvs []corev1.PersistentVolume) [][]string {
+	var classes []string
+	var capacities []corev1.ResourceList
+	for _, pv := range pvs {
+		class := pv.Spec.StorageClassName
+		if class == "" {
+			class = "<none>"
+		}
+		classes = append(classes, class)
+		capacities = append(capacities, pv.Spec.Capacity)
+	}
+	return getStorageTotals(classes, capacities)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetWastedCapacity(t *testing.T) {
+	tests := []struct {
+		name string
+		pvs  []corev1.PersistentVolume
+		want [][]string
+	}{
+		{
+			name: "no PersistentVolumes are expected to waste nothing",
+			pvs:  nil,
+			want: nil,
+		},
+		{
+			name: "capacity is expected to be summed per StorageClass",
+			pvs: []corev1.PersistentVolume{
+				newTestPV("pv", "standard", "10Gi"),
+				newTestPV("pv", "fast", "1Gi"),
+				newTestPV("pv", "standard", "5Gi"),
+				newTestPV("pv", "", "512Mi"),
+			},
+			want: [][]string{
+				{"<none>", "1", "512Mi"},
+				{"fast", "1", "1Gi"},
+				{"standard", "2", "15Gi"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getWastedCapacity(tc.pvs)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetPVPhaseTransitionTime(t *testing.T) {
+	ts := time.Date(2020, time.December, 1, 12, 0, 0, 0, time.UTC)
+
+	pv := corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: ts.Add(-time.Hour)}, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:phase":{}}}`)}},
+				{Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: ts}, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:phase":{}}}`)}},
+				{Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: ts.Add(time.Hour)}, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{}}}`)}},
+				{Operation: metav1.ManagedFieldsOperationApply},
+			},
+		},
+	}
+
+	got := getPVPhaseTransitionTime(pv)
+	assert.True(t, ts.Equal(got.Time))
+	assert.True(t, getPVPhaseTransitionTime(corev1.PersistentVolume{}).Time.IsZero())
+}
@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
@@ -64,6 +64,7 @@ func (o *UnclaimedPVsOptions) Run(ctx context.Context, noHeader bool) error {
 	}
 
 	var matrix [][]string
+	var uids []types.UID
 
 	for _, pv := range pvs.Items {
 		if pv.Status.Phase == "Available" {
@@ -72,15 +73,20 @@ func (o *UnclaimedPVsOptions) Run(ctx context.Context, noHeader bool) error {
 			if o.allNamespaces {
 				row = append([]string{pv.Namespace}, row...)
 			}
+			uids = append(uids, pv.UID)
 			matrix = append(matrix, row)
 		}
 	}
 
 	headers := []string{"NAME", "RECLAIM POLICY", "STORAGECLASS", "AGE"}
 
-	buf := bytes.NewBuffer(nil)
-	writeResults(buf, headers, matrix, o.namespace, noHeader)
-	fmt.Printf("%s", buf.String())
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -14,6 +14,9 @@ func newPVsCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Comma
 		SilenceUsage: true,
 	}
 
+	cmd.AddCommand(newFailedPVsCommand(factory, options))
+	cmd.AddCommand(newReclaimPVsCommand(factory, options))
+	cmd.AddCommand(newReleasedPVsCommand(factory, options))
 	cmd.AddCommand(newUnclaimedPVsCommand(factory, options))
 
 	return cmd
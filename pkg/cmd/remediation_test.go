@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "yes is expected to confirm", input: "yes\n", want: true},
+		{name: "y is expected to confirm", input: "Y\n", want: true},
+		{name: "no is expected to abort", input: "n\n", want: false},
+		{name: "empty answer is expected to abort", input: "", want: false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			got, err := confirm(strings.NewReader(tc.input), out, "Continue?")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, "Continue? [y/N]: ", out.String())
+		})
+	}
+}
+
+func TestBackupObject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "janitor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pv := corev1.PersistentVolume{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolume"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+	}
+
+	path, err := backupObject(dir, pv.Kind, "", pv.Name, pv)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "persistentvolume_pv-1_"))
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "kind: PersistentVolume")
+	assert.Contains(t, string(data), "name: pv-1")
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// NoEndpointsServicesOptions embeds JanitorOptions struct.
+type NoEndpointsServicesOptions struct {
+	JanitorOptions
+}
+
+// newNoEndpointsServicesOptions creates an instance of NoEndpointsServicesOptions.
+func newNoEndpointsServicesOptions(options JanitorOptions) *NoEndpointsServicesOptions {
+	return &NoEndpointsServicesOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newNoEndpointsServicesCommand returns a cobra command wrapping NoEndpointsServicesOptions.
+func newNoEndpointsServicesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newNoEndpointsServicesOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "no-endpoints",
+		Short:        "List Services with a selector that have no ready endpoints, no matching Pods or an unknown targetPort",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run lists Services that cannot route traffic to any Pod.
+func (o *NoEndpointsServicesOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	services, err := client.CoreV1().Services(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	pods, err := client.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	readyEndpoints, err := getReadyEndpoints(ctx, client, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 || svc.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+
+		selected := getSelectedPods(svc, pods.Items)
+		ready := readyEndpoints[types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}]
+
+		problems := getServiceProblems(svc, selected, ready)
+		if len(problems) == 0 {
+			continue
+		}
+
+		age := getAge(svc.CreationTimestamp)
+		selector := labels.SelectorFromSet(svc.Spec.Selector).String()
+		row := []string{svc.Name, string(svc.Spec.Type), selector, strconv.Itoa(len(selected)), strconv.Itoa(ready), strings.Join(problems, ","), age}
+		if o.allNamespaces {
+			row = append([]string{svc.Namespace}, row...)
+		}
+		uids = append(uids, svc.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "TYPE", "SELECTOR", "PODS", "READY ENDPOINTS", "PROBLEMS", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace, "PODS", "READY ENDPOINTS"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getSelectedPods returns the Pods that are selected by the Service.
+func getSelectedPods(svc corev1.Service, pods []corev1.Pod) []corev1.Pod {
+	var selected []corev1.Pod
+
+	selector := labels.SelectorFromSet(svc.Spec.Selector)
+	for _, pod := range pods {
+		if pod.Namespace == svc.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+			selected = append(selected, pod)
+		}
+	}
+	return selected
+}
+
+// getServiceProblems returns the reasons a Service with a selector cannot serve traffic.
+// The pods are the Pods selected by the Service.
+func getServiceProblems(svc corev1.Service, pods []corev1.Pod, readyEndpoints int) []string {
+	var problems []string
+
+	if len(pods) == 0 {
+		problems = append(problems, "NoMatchingPods")
+	}
+
+	if readyEndpoints == 0 {
+		problems = append(problems, "NoReadyEndpoints")
+	}
+
+	for _, port := range svc.Spec.Ports {
+		if len(pods) > 0 && !hasTargetPort(port.TargetPort, pods) {
+			problems = append(problems, fmt.Sprintf("TargetPortNotFound(%s)", port.TargetPort.String()))
+		}
+	}
+
+	return problems
+}
+
+// hasTargetPort checks whether at least one of the Pods exposes the targetPort.
+// Numeric ports are only verified against Pods that declare container ports.
+func hasTargetPort(targetPort intstr.IntOrString, pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		declared := false
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				declared = true
+				if targetPort.Type == intstr.String && port.Name == targetPort.StrVal {
+					return true
+				}
+				if targetPort.Type == intstr.Int && port.ContainerPort == targetPort.IntVal {
+					return true
+				}
+			}
+		}
+
+		if !declared && targetPort.Type == intstr.Int {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetServiceProblems(t *testing.T) {
+	withHTTPPort := newTestPod(testNamespace, "web-0", withContainers(corev1.Container{Name: "web", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}))
+	withoutPorts := newTestPod(testNamespace, "web-0")
+
+	tests := []struct {
+		name           string
+		svc            corev1.Service
+		pods           []corev1.Pod
+		readyEndpoints int
+		want           []string
+	}{
+		{
+			name:           "Service with ready endpoints and a matching named port is expected to be healthy",
+			svc:            newTestService(testNamespace, "web", intstr.FromString("http")),
+			pods:           []corev1.Pod{withHTTPPort},
+			readyEndpoints: 1,
+			want:           nil,
+		},
+		{
+			name:           "Service whose selector matches no Pods is expected to be reported",
+			svc:            newTestService(testNamespace, "web", intstr.FromInt(8080)),
+			pods:           nil,
+			readyEndpoints: 0,
+			want:           []string{"NoMatchingPods", "NoReadyEndpoints"},
+		},
+		{
+			name:           "Service with Pods but no ready endpoints is expected to be reported",
+			svc:            newTestService(testNamespace, "web", intstr.FromInt(8080)),
+			pods:           []corev1.Pod{withoutPorts},
+			readyEndpoints: 0,
+			want:           []string{"NoReadyEndpoints"},
+		},
+		{
+			name:           "Service with a named targetPort missing on the Pods is expected to be reported",
+			svc:            newTestService(testNamespace, "web", intstr.FromString("metrics")),
+			pods:           []corev1.Pod{withHTTPPort},
+			readyEndpoints: 1,
+			want:           []string{"TargetPortNotFound(metrics)"},
+		},
+		{
+			name:           "Service with a numeric targetPort not declared by the Pods is expected to be reported",
+			svc:            newTestService(testNamespace, "web", intstr.FromInt(9090)),
+			pods:           []corev1.Pod{withHTTPPort},
+			readyEndpoints: 1,
+			want:           []string{"TargetPortNotFound(9090)"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getServiceProblems(tc.svc, tc.pods, tc.readyEndpoints)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// PendingLBServicesOptions embeds JanitorOptions struct.
+type PendingLBServicesOptions struct {
+	JanitorOptions
+	olderThan time.Duration
+}
+
+// pendingLBService is a LoadBalancer Service still waiting for an external IP.
+type pendingLBService struct {
+	svc      corev1.Service
+	warnings int
+	reason   string
+	message  string
+}
+
+// newPendingLBServicesOptions creates an instance of PendingLBServicesOptions.
+func newPendingLBServicesOptions(options JanitorOptions) *PendingLBServicesOptions {
+	return &PendingLBServicesOptions{
+		JanitorOptions: options,
+	}
+}
+
+// newPendingLBServicesCommand returns a cobra command wrapping PendingLBServicesOptions.
+func newPendingLBServicesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+	o := newPendingLBServicesOptions(options)
+
+	cmd := &cobra.Command{
+		Use:          "pending-lb",
+		Short:        "List LoadBalancer Services that are still waiting for an external IP",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(factory, c); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			noHeader := c.Flag("no-headers").Changed
+			if err := o.Run(ctx, noHeader); err != nil {
+				fmt.Fprintln(options.Streams.ErrOut, err.Error())
+				return nil
+			}
+			return nil
+		},
+	}
+
+	o.ResourceBuilderFlags.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&o.olderThan, "older-than", 5*time.Minute, "List Services that have been waiting for an external IP longer than this duration.")
+
+	return cmd
+}
+
+// Run lists LoadBalancer Services without an ingress point. Only the latest
+// Warning Event is shown, --events attaches the latest N Events of each Service.
+func (o *PendingLBServicesOptions) Run(ctx context.Context, noHeader bool) error {
+	client, err := o.GetClient()
+	if err != nil {
+		return err
+	}
+
+	services, err := client.CoreV1().Services(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	events, err := getWarningEvents(ctx, client, o.namespace, "Service")
+	if err != nil {
+		return err
+	}
+
+	var matrix [][]string
+	var uids []types.UID
+
+	for _, pending := range getPendingLBServices(services.Items, events, o.olderThan, time.Now()) {
+		svc := pending.svc
+		age := getAge(svc.CreationTimestamp)
+		row := []string{svc.Name, strconv.Itoa(pending.warnings), pending.reason, pending.message, age}
+		if o.allNamespaces {
+			row = append([]string{svc.Namespace}, row...)
+		}
+		uids = append(uids, svc.UID)
+		matrix = append(matrix, row)
+	}
+
+	headers := []string{"NAME", "WARNINGS", "LAST REASON", "LAST MESSAGE", "AGE"}
+
+	p, err := o.newPrinter(ctx, o.namespace, noHeader)
+	if err != nil {
+		return err
+	}
+	if err := p.printResults(headers, matrix, uids, o.namespace, "WARNINGS"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPendingLBServices returns the LoadBalancer Services without an ingress point
+// created more than olderThan ago, with their number of warnings and the reason
+// and message of their latest Warning Event. The events are the Warning Events
+// of every Service, sorted from newest to oldest.
+func getPendingLBServices(services []corev1.Service, events map[types.UID][]corev1.Event, olderThan time.Duration, now time.Time) []pendingLBService {
+	var pending []pendingLBService
+	for _, svc := range services {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || len(svc.Status.LoadBalancer.Ingress) > 0 {
+			continue
+		}
+
+		if now.Sub(svc.CreationTimestamp.Time) < olderThan {
+			continue
+		}
+
+		p := pendingLBService{svc: svc, reason: "<none>", message: "<none>"}
+		for i, event := range events[svc.UID] {
+			if i == 0 {
+				p.reason, p.message = event.Reason, event.Message
+			}
+			if event.Count > 0 {
+				p.warnings += int(event.Count)
+			} else {
+				p.warnings++
+			}
+		}
+		pending = append(pending, p)
+	}
+	return pending
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetPendingLBServices(t *testing.T) {
+	now := time.Now()
+
+	newLB := func(name string, created time.Time, ingress ...corev1.LoadBalancerIngress) corev1.Service {
+		svc := newTestService(testNamespace, name, intstr.FromInt(8080))
+		svc.UID = types.UID(name)
+		svc.CreationTimestamp = metav1.NewTime(created)
+		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		svc.Status.LoadBalancer.Ingress = ingress
+		return svc
+	}
+
+	pending := newLB("pending", now.Add(-time.Hour))
+	recent := newLB("recent", now.Add(-time.Minute))
+	ready := newLB("ready", now.Add(-time.Hour), corev1.LoadBalancerIngress{IP: "203.0.113.10"})
+	clusterIP := newTestService(testNamespace, "internal", intstr.FromInt(8080))
+	clusterIP.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+
+	events := map[types.UID][]corev1.Event{
+		pending.UID: {
+			{Reason: "SyncLoadBalancerFailed", Message: "quota exceeded", Count: 4},
+			{Reason: "EnsuringLoadBalancer", Message: "ensuring load balancer"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		services []corev1.Service
+		events   map[types.UID][]corev1.Event
+		want     []pendingLBService
+	}{
+		{
+			name:     "Service waiting for an external IP is expected to show its latest warning and count every occurrence",
+			services: []corev1.Service{pending},
+			events:   events,
+			want:     []pendingLBService{{svc: pending, warnings: 5, reason: "SyncLoadBalancerFailed", message: "quota exceeded"}},
+		},
+		{
+			name:     "Service without warnings is expected to be reported without a reason",
+			services: []corev1.Service{pending},
+			want:     []pendingLBService{{svc: pending, reason: "<none>", message: "<none>"}},
+		},
+		{
+			name:     "Services that are recent, have an ingress point or are not LoadBalancers are not expected to be reported",
+			services: []corev1.Service{recent, ready, clusterIP},
+			events:   events,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := getPendingLBServices(tt.services, tt.events, 5*time.Minute, now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// newServicesCommand provides the base command when called without any subcommands.
+func newServicesCommand(factory cmdutil.Factory, options JanitorOptions) *cobra.Command {
+
+	cmd := &cobra.Command{
+		Use:          "services",
+		Short:        "Find Services in a problematic state",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newNoEndpointsServicesCommand(factory, options))
+	cmd.AddCommand(newPendingLBServicesCommand(factory, options))
+
+	return cmd
+}
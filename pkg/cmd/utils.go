@@ -1,18 +1,39 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 )
 
+// apiResource is a resource served by the cluster that can be listed.
+type apiResource struct {
+	schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
 // getAge returns the age of an object.
 func getAge(timestamp metav1.Time) string {
 	if timestamp.IsZero() {
@@ -115,6 +136,163 @@ func isPodReady(pod corev1.Pod) bool {
 	return false
 }
 
+// getPodClaimNames returns the names of the PersistentVolumeClaims mounted by the Pod.
+func getPodClaimNames(pod corev1.Pod) []string {
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claims
+}
+
+// getMatchingPDBs returns the PodDisruptionBudgets whose selector covers the Pod.
+func getMatchingPDBs(pod corev1.Pod, pdbs []policyv1beta1.PodDisruptionBudget) []policyv1beta1.PodDisruptionBudget {
+	var matching []policyv1beta1.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, pdb)
+		}
+	}
+	return matching
+}
+
+// getJobFailedCondition returns the Failed condition of the Job when it is set to True.
+func getJobFailedCondition(job batchv1.Job) *batchv1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// isJobFailed checks whether the Job has a Failed condition set to True.
+func isJobFailed(job batchv1.Job) bool {
+	return getJobFailedCondition(job) != nil
+}
+
+// isJobComplete checks whether the Job has a Complete condition set to True.
+func isJobComplete(job batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobComplete && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// getReadyEndpoints returns the number of ready endpoints of every Service in the namespace.
+// Both Endpoints and EndpointSlices are consulted, EndpointSlices are skipped
+// when the API is not served by the cluster.
+func getReadyEndpoints(ctx context.Context, client kubernetes.Interface, namespace string) (map[types.NamespacedName]int, error) {
+	ready := make(map[types.NamespacedName]int)
+
+	endpoints, err := client.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ep := range endpoints.Items {
+		key := types.NamespacedName{Namespace: ep.Namespace, Name: ep.Name}
+		for _, subset := range ep.Subsets {
+			ready[key] += len(subset.Addresses)
+		}
+	}
+
+	slices, err := client.DiscoveryV1beta1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return ready, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fromSlices := make(map[types.NamespacedName]int)
+	for _, slice := range slices.Items {
+		svcName, ok := slice.Labels[discoveryv1beta1.LabelServiceName]
+		if !ok {
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: slice.Namespace, Name: svcName}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				fromSlices[key]++
+			}
+		}
+	}
+
+	for key, count := range fromSlices {
+		if count > ready[key] {
+			ready[key] = count
+		}
+	}
+
+	return ready, nil
+}
+
+// getEventTime returns the last time an Event was observed.
+func getEventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// getWarningEvents returns the Warning Events of the given kind in the namespace,
+// grouped by the UID of the involved object and sorted from newest to oldest.
+func getWarningEvents(ctx context.Context, client kubernetes.Interface, namespace, kind string) (map[types.UID][]corev1.Event, error) {
+	return getEvents(ctx, client, namespace, kind, corev1.EventTypeWarning)
+}
+
+// getEvents returns the Events of the given kind and type in the namespace,
+// grouped by the UID of the involved object and sorted from newest to oldest.
+// An empty kind or eventType returns Events of every kind or type.
+func getEvents(ctx context.Context, client kubernetes.Interface, namespace, kind, eventType string) (map[types.UID][]corev1.Event, error) {
+	var selectors []string
+	if kind != "" {
+		selectors = append(selectors, "involvedObject.kind="+kind)
+	}
+	if eventType != "" {
+		selectors = append(selectors, "type="+eventType)
+	}
+	options := metav1.ListOptions{FieldSelector: strings.Join(selectors, ",")}
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[types.UID][]corev1.Event)
+	for _, event := range events.Items {
+		grouped[event.InvolvedObject.UID] = append(grouped[event.InvolvedObject.UID], event)
+	}
+
+	for _, list := range grouped {
+		sort.Slice(list, func(i, j int) bool {
+			return getEventTime(list[i]).After(getEventTime(list[j]))
+		})
+	}
+
+	return grouped, nil
+}
+
 // writeResults consolidates the final output in the out io.Writer.
 func writeResults(out io.Writer, headers []string, matrix [][]string, namespace string, noHeader bool) {
 	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
@@ -139,3 +317,114 @@ func writeResults(out io.Writer, headers []string, matrix [][]string, namespace
 		}
 	}
 }
+
+// getServedResources returns the listable resources served by the cluster, in
+// their preferred version. Groups whose discovery fails, such as unavailable
+// aggregated APIs, are skipped.
+func getServedResources(client discovery.DiscoveryInterface, namespacedOnly bool) ([]apiResource, error) {
+	var lists []*metav1.APIResourceList
+	var err error
+	if namespacedOnly {
+		lists, err = discovery.ServerPreferredNamespacedResources(client)
+	} else {
+		lists, err = discovery.ServerPreferredResources(client)
+	}
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+
+	return getListableResources(lists), nil
+}
+
+// getListableResources returns the resources of the lists that support the list
+// verb, leaving out subresources.
+func getListableResources(lists []*metav1.APIResourceList) []apiResource {
+	var resources []apiResource
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") || !sets.NewString(resource.Verbs...).Has("list") {
+				continue
+			}
+
+			resources = append(resources, apiResource{
+				GroupVersionResource: gv.WithResource(resource.Name),
+				kind:                 resource.Kind,
+				namespaced:           resource.Namespaced,
+			})
+		}
+	}
+	return resources
+}
+
+// getUnseenObjects returns the objects whose UID is not in seen yet and adds
+// them to it. Some resources are served by several groups, for example Events
+// by the core and events.k8s.io groups, so walking every preferred resource
+// lists the same objects more than once.
+func getUnseenObjects(seen map[types.UID]bool, objects []unstructured.Unstructured) []unstructured.Unstructured {
+	var unseen []unstructured.Unstructured
+	for _, obj := range objects {
+		if seen[obj.GetUID()] {
+			continue
+		}
+		seen[obj.GetUID()] = true
+		unseen = append(unseen, obj)
+	}
+	return unseen
+}
+
+// writeClusterResults consolidates the final output of cluster-scoped objects in the out io.Writer.
+func writeClusterResults(out io.Writer, headers []string, matrix [][]string, noHeader bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	defer w.Flush()
+
+	if len(matrix) == 0 {
+		fmt.Fprintln(w, "No resources found")
+		return
+	}
+
+	if !noHeader {
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	for _, row := range matrix {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+// getStorageTotals returns the number of objects and their total storage for
+// each key, sorted by key. The key and the capacity of an object share its index.
+func getStorageTotals(keys []string, capacities []corev1.ResourceList) [][]string {
+	counts := make(map[string]int)
+	totals := make(map[string]*resource.Quantity)
+
+	for i, key := range keys {
+		counts[key]++
+		if totals[key] == nil {
+			totals[key] = resource.NewQuantity(0, resource.BinarySI)
+		}
+		if storage, ok := capacities[i][corev1.ResourceStorage]; ok {
+			totals[key].Add(storage)
+		}
+	}
+
+	var sorted []string
+	for key := range counts {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var matrix [][]string
+	for _, key := range sorted {
+		matrix = append(matrix, []string{key, strconv.Itoa(counts[key]), totals[key].String()})
+	}
+	return matrix
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestIsPodHealthy(t *testing.T) {
@@ -694,3 +696,183 @@ func TestGetAge(t *testing.T) {
 		})
 	}
 }
+
+func TestIsJobFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		job  batchv1.Job
+		want bool
+	}{
+		{
+			name: "Job with a Failed condition set to True is expected to be failed",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionTrue,
+							Reason: "BackoffLimitExceeded",
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Job with a Failed condition set to False is not expected to be failed",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionFalse,
+							Reason: "BackoffLimitExceeded",
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Job that completed is not expected to be failed",
+			job: batchv1.Job{
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobComplete,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := isJobFailed(tc.job)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetEventTime(t *testing.T) {
+	ts := time.Date(2020, time.December, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		event corev1.Event
+		want  time.Time
+	}{
+		{
+			name: "Event with a last timestamp is expected to use it",
+			event: corev1.Event{
+				FirstTimestamp: metav1.NewTime(ts.Add(-time.Hour)),
+				LastTimestamp:  metav1.NewTime(ts),
+			},
+			want: ts,
+		},
+		{
+			name: "Event with only an event time is expected to use it",
+			event: corev1.Event{
+				EventTime: metav1.NewMicroTime(ts),
+			},
+			want: ts,
+		},
+		{
+			name: "Event without timestamps is expected to use its creation time",
+			event: corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(ts),
+				},
+			},
+			want: ts,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getEventTime(tc.event)
+			assert.True(t, tc.want.Equal(got))
+		})
+	}
+}
+
+func TestWriteClusterResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		matrix   [][]string
+		noHeader bool
+		want     string
+	}{
+		{
+			name:     "expect headers without a Namespace column",
+			headers:  []string{"NAME", "STATUS"},
+			matrix:   [][]string{{"pv-1", "Released"}},
+			noHeader: false,
+			want:     "NAME   STATUS\npv-1   Released\n",
+		},
+		{
+			name:     "expect no headers",
+			headers:  []string{"NAME", "STATUS"},
+			matrix:   [][]string{{"pv-1", "Released"}},
+			noHeader: true,
+			want:     "pv-1   Released\n",
+		},
+		{
+			name:     "expect no resources found",
+			headers:  []string{"NAME", "STATUS"},
+			matrix:   [][]string{},
+			noHeader: false,
+			want:     "No resources found\n",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			out := &bytes.Buffer{}
+			writeClusterResults(out, tc.headers, tc.matrix, tc.noHeader)
+			assert.Equal(t, tc.want, out.String())
+		})
+	}
+}
+
+func TestGetListableResources(t *testing.T) {
+	t.Parallel()
+
+	lists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list", "delete"}},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+				{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}},
+			},
+		},
+		nil,
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"list"}},
+			},
+		},
+		{
+			GroupVersion: "storage.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "storageclasses", Kind: "StorageClass", Verbs: []string{"list"}},
+			},
+		},
+	}
+
+	want := []apiResource{
+		{GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, kind: "Pod", namespaced: true},
+		{GroupVersionResource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, kind: "Deployment", namespaced: true},
+		{GroupVersionResource: schema.GroupVersionResource{Group: "storage.k8s.io", Version: "v1", Resource: "storageclasses"}, kind: "StorageClass"},
+	}
+	assert.Equal(t, want, getListableResources(lists))
+}